@@ -9,6 +9,8 @@ import (
 	"github.com/akash329d/storj_exporter/models"
 )
 
+const notificationsPageLimit = 100
+
 type ApiClient struct {
 	BaseURL    string
 	NodeID	 string
@@ -76,3 +78,20 @@ func (c *ApiClient) Satellite(satelliteId string) (models.SatelliteResponse, err
 	}
 	return data, nil
 }
+
+// Notifications walks every page of the node's notification list and returns all notifications.
+func (c *ApiClient) Notifications() ([]models.Notification, error) {
+	var notifications []models.Notification
+	for page := 1; ; page++ {
+		var data models.NotificationsResponse
+		notificationsApiUrl := fmt.Sprintf("/api/notifications/list?page=%d&limit=%d", page, notificationsPageLimit)
+		err := c.get(notificationsApiUrl, &data)
+		if err != nil {
+			return notifications, fmt.Errorf("API Request for notifications failed with API URL %s, %w", notificationsApiUrl, err)
+		}
+		notifications = append(notifications, data.Page.Notifications...)
+		if page >= data.Page.PageCount {
+			return notifications, nil
+		}
+	}
+}
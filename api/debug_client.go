@@ -0,0 +1,216 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+var invalidMetricChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// DebugClient reads monkit statistics from the storagenode debug address (debug.addr).
+type DebugClient struct {
+	BaseURL    string
+	httpClient *http.Client
+}
+
+func NewDebugClient(baseURL string) *DebugClient {
+	return &DebugClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+// Metrics returns the monkit statistics of the node. The Prometheus formatted /metrics endpoint
+// is preferred, older nodes which only serve /mon/stats are read from there instead.
+func (c *DebugClient) Metrics() ([]models.DebugMetric, error) {
+	metrics, err := c.prometheusMetrics()
+	if err == nil {
+		return metrics, nil
+	}
+
+	metrics, monErr := c.monStats()
+	if monErr != nil {
+		return nil, fmt.Errorf("Debug request for metrics failed: %v, falling back to /mon/stats failed: %w", err, monErr)
+	}
+	return metrics, nil
+}
+
+func (c *DebugClient) fetch(endpoint string) (*http.Response, error) {
+	resp, err := c.httpClient.Get(c.BaseURL + endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("debug request for %s failed with status code: %d", endpoint, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (c *DebugClient) prometheusMetrics() ([]models.DebugMetric, error) {
+	resp, err := c.fetch("/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []models.DebugMetric
+	for name, family := range families {
+		for _, metric := range family.GetMetric() {
+			metrics = append(metrics, flattenMetric(name, family.GetType(), metric)...)
+		}
+	}
+	return metrics, nil
+}
+
+func flattenMetric(name string, metricType dto.MetricType, metric *dto.Metric) []models.DebugMetric {
+	labels := make(map[string]string, len(metric.GetLabel()))
+	for _, label := range metric.GetLabel() {
+		labels[label.GetName()] = label.GetValue()
+	}
+
+	sample := func(suffix string, value float64, extra ...string) models.DebugMetric {
+		// The _sum, _count and _bucket samples of histograms and summaries only ever increase.
+		sampleType := models.DebugUntyped
+		switch {
+		case metricType == dto.MetricType_COUNTER || suffix != "":
+			sampleType = models.DebugCounter
+		case metricType == dto.MetricType_GAUGE:
+			sampleType = models.DebugGauge
+		}
+
+		sampleLabels := labels
+		if len(extra) == 2 {
+			sampleLabels = make(map[string]string, len(labels)+1)
+			for k, v := range labels {
+				sampleLabels[k] = v
+			}
+			sampleLabels[extra[0]] = extra[1]
+		}
+		return models.DebugMetric{Name: name + suffix, Labels: sampleLabels, Value: value, Type: sampleType}
+	}
+
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		return []models.DebugMetric{sample("", metric.GetCounter().GetValue())}
+	case dto.MetricType_GAUGE:
+		return []models.DebugMetric{sample("", metric.GetGauge().GetValue())}
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		samples := []models.DebugMetric{
+			sample("_sum", summary.GetSampleSum()),
+			sample("_count", float64(summary.GetSampleCount())),
+		}
+		for _, quantile := range summary.GetQuantile() {
+			samples = append(samples, sample("", quantile.GetValue(), "quantile", strconv.FormatFloat(quantile.GetQuantile(), 'g', -1, 64)))
+		}
+		return samples
+	case dto.MetricType_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		samples := []models.DebugMetric{
+			sample("_sum", histogram.GetSampleSum()),
+			sample("_count", float64(histogram.GetSampleCount())),
+		}
+		for _, bucket := range histogram.GetBucket() {
+			samples = append(samples, sample("_bucket", float64(bucket.GetCumulativeCount()), "le", strconv.FormatFloat(bucket.GetUpperBound(), 'g', -1, 64)))
+		}
+		return samples
+	default:
+		return []models.DebugMetric{sample("", metric.GetUntyped().GetValue())}
+	}
+}
+
+// monCounterFields are the monkit fields that only ever increase, such as the call counts of functions and meters.
+var monCounterFields = map[string]bool{
+	"total":     true,
+	"count":     true,
+	"successes": true,
+	"errors":    true,
+	"failures":  true,
+	"panics":    true,
+}
+
+// monStats parses the monkit text format, one "<measurement>[,<tag>=<value>...] <field> <value>" sample per line.
+func (c *DebugClient) monStats() ([]models.DebugMetric, error) {
+	resp, err := c.fetch("/mon/stats")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var metrics []models.DebugMetric
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		valueIndex := strings.LastIndexByte(line, ' ')
+		if valueIndex < 0 {
+			continue
+		}
+		fieldIndex := strings.LastIndexByte(line[:valueIndex], ' ')
+		if fieldIndex < 0 {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(line[valueIndex+1:], 64)
+		if err != nil {
+			continue
+		}
+
+		parts := splitUnescaped(line[:fieldIndex], ',')
+		labels := make(map[string]string, len(parts)-1)
+		for _, tag := range parts[1:] {
+			kv := splitUnescaped(tag, '=')
+			if len(kv) == 2 {
+				labels[invalidMetricChars.ReplaceAllString(kv[0], "_")] = kv[1]
+			}
+		}
+
+		field := line[fieldIndex+1 : valueIndex]
+		metricType := models.DebugUntyped
+		if monCounterFields[field] {
+			metricType = models.DebugCounter
+		}
+
+		name := invalidMetricChars.ReplaceAllString(parts[0]+"_"+field, "_")
+		metrics = append(metrics, models.DebugMetric{Name: name, Labels: labels, Value: value, Type: metricType})
+	}
+	return metrics, scanner.Err()
+}
+
+// splitUnescaped splits s on sep, ignoring separators escaped with a backslash, and removes the escaping.
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	var current strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			current.WriteByte(s[i])
+		case s[i] == sep:
+			parts = append(parts, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(s[i])
+		}
+	}
+	return append(parts, current.String())
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+const DefaultDockerHost = "unix:///var/run/docker.sock"
+
+// DockerClient talks to the Docker Engine API, either over a unix socket or plain TCP.
+type DockerClient struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+func NewDockerClient(host string) (*DockerClient, error) {
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Docker host %s: %w", host, err)
+	}
+
+	client := &DockerClient{
+		httpClient: &http.Client{},
+		timeout:    time.Second * 10,
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		client.baseURL = "http://docker"
+		client.httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+	case "tcp", "http":
+		client.baseURL = "http://" + u.Host
+	default:
+		return nil, fmt.Errorf("unsupported Docker host scheme %q", u.Scheme)
+	}
+	return client, nil
+}
+
+// stream performs a GET request without a deadline, for endpoints that stream their response.
+func (c *DockerClient) stream(ctx context.Context, endpoint string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Docker API request failed with status code: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func (c *DockerClient) get(endpoint string, target interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	body, err := c.stream(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return json.NewDecoder(body).Decode(target)
+}
+
+type dockerContainer struct {
+	Config struct {
+		Tty bool `json:"Tty"`
+	} `json:"Config"`
+	State struct {
+		StartedAt time.Time `json:"StartedAt"`
+	} `json:"State"`
+}
+
+type dockerStats struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+	} `json:"cpu_stats"`
+	MemoryStats struct {
+		Usage int64            `json:"usage"`
+		Stats map[string]int64 `json:"stats"`
+	} `json:"memory_stats"`
+	BlkioStats struct {
+		IOServiceBytesRecursive []struct {
+			Op    string `json:"op"`
+			Value int64  `json:"value"`
+		} `json:"io_service_bytes_recursive"`
+	} `json:"blkio_stats"`
+}
+
+// ContainerStats returns the resource usage of a container. Memory excludes the inactive page cache,
+// matching the working set reported by `docker stats`.
+func (c *DockerClient) ContainerStats(container string) (models.ProcessStats, error) {
+	stats := models.ProcessStats{OpenFDs: -1}
+
+	var info dockerContainer
+	if err := c.get("/containers/"+url.PathEscape(container)+"/json", &info); err != nil {
+		return stats, fmt.Errorf("Docker API request for container %s failed: %w", container, err)
+	}
+	stats.StartedAt = info.State.StartedAt
+
+	var data dockerStats
+	if err := c.get("/containers/"+url.PathEscape(container)+"/stats?stream=0&one-shot=1", &data); err != nil {
+		return stats, fmt.Errorf("Docker API request for stats of container %s failed: %w", container, err)
+	}
+
+	stats.CPUSeconds = float64(data.CPUStats.CPUUsage.TotalUsage) / float64(time.Second)
+	// cgroup v1 reports total_inactive_file, cgroup v2 inactive_file.
+	inactive, ok := data.MemoryStats.Stats["inactive_file"]
+	if !ok {
+		inactive = data.MemoryStats.Stats["total_inactive_file"]
+	}
+	stats.MemoryBytes = data.MemoryStats.Usage
+	if inactive < stats.MemoryBytes {
+		stats.MemoryBytes -= inactive
+	}
+
+	for _, entry := range data.BlkioStats.IOServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.ReadBytes += entry.Value
+			stats.HasIO = true
+		case "write":
+			stats.WriteBytes += entry.Value
+			stats.HasIO = true
+		}
+	}
+	return stats, nil
+}
+
+// ContainerLogs follows the stdout and stderr of a container from the given time on. Every line is prefixed
+// with its RFC 3339 timestamp and a space, so a reader can resume after the last line it saw.
+// The returned stream is demultiplexed, so it can be read as plain text.
+func (c *DockerClient) ContainerLogs(ctx context.Context, container string, since time.Time) (io.ReadCloser, error) {
+	var info dockerContainer
+	if err := c.get("/containers/"+url.PathEscape(container)+"/json", &info); err != nil {
+		return nil, fmt.Errorf("Docker API request for container %s failed: %w", container, err)
+	}
+
+	query := url.Values{}
+	query.Set("follow", "1")
+	query.Set("stdout", "1")
+	query.Set("stderr", "1")
+	query.Set("timestamps", "1")
+	query.Set("since", fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond()))
+
+	body, err := c.stream(ctx, "/containers/"+url.PathEscape(container)+"/logs?"+query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("Docker API request for logs of container %s failed: %w", container, err)
+	}
+
+	// Containers without a TTY multiplex stdout and stderr into frames with an 8 byte header.
+	if info.Config.Tty {
+		return body, nil
+	}
+	return &dockerLogReader{body: body}, nil
+}
+
+type dockerLogReader struct {
+	body      io.ReadCloser
+	remaining uint32
+}
+
+func (r *dockerLogReader) Read(p []byte) (int, error) {
+	for r.remaining == 0 {
+		var header [8]byte
+		if _, err := io.ReadFull(r.body, header[:]); err != nil {
+			return 0, err
+		}
+		r.remaining = binary.BigEndian.Uint32(header[4:])
+	}
+	if uint32(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.body.Read(p)
+	r.remaining -= uint32(n)
+	return n, err
+}
+
+func (r *dockerLogReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The storagenode private address speaks DRPC, Storj's lightweight replacement for gRPC.
+// Only unary calls are needed, so instead of pulling in the full RPC stack we implement
+// the small part of the wire protocol they use.
+// See https://github.com/storj/drpc/blob/main/docs/protocol.md
+const (
+	drpcKindInvoke    = 1
+	drpcKindMessage   = 2
+	drpcKindError     = 3
+	drpcKindClose     = 5
+	drpcKindCloseSend = 6
+)
+
+// drpcHeader is sent before the first packet so the node's listener (drpcmigrate) routes the
+// connection to the DRPC server instead of treating it as a legacy gRPC connection.
+const drpcHeader = "DRPC!!!1"
+
+type drpcFrame struct {
+	kind    byte
+	done    bool
+	control bool
+	stream  uint64
+	message uint64
+	data    []byte
+}
+
+func appendDrpcFrame(buf []byte, fr drpcFrame) []byte {
+	header := fr.kind << 1
+	if fr.done {
+		header |= 0b00000001
+	}
+	if fr.control {
+		header |= 0b10000000
+	}
+	buf = append(buf, header)
+	buf = protowire.AppendVarint(buf, fr.stream)
+	buf = protowire.AppendVarint(buf, fr.message)
+	buf = protowire.AppendVarint(buf, uint64(len(fr.data)))
+	return append(buf, fr.data...)
+}
+
+func readDrpcFrame(r *bufio.Reader) (drpcFrame, error) {
+	var fr drpcFrame
+
+	header, err := r.ReadByte()
+	if err != nil {
+		return fr, err
+	}
+	fr.kind = (header >> 1) & 0b00111111
+	fr.done = header&0b00000001 != 0
+	fr.control = header&0b10000000 != 0
+
+	if fr.stream, err = binary.ReadUvarint(r); err != nil {
+		return fr, err
+	}
+	if fr.message, err = binary.ReadUvarint(r); err != nil {
+		return fr, err
+	}
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return fr, err
+	}
+
+	fr.data = make([]byte, length)
+	_, err = io.ReadFull(r, fr.data)
+	return fr, err
+}
+
+// drpcInvoke performs a single unary call on a fresh connection and returns the encoded response message.
+func drpcInvoke(address string, timeout time.Duration, rpc string, request []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	const stream = 1
+	buf := []byte(drpcHeader)
+	buf = appendDrpcFrame(buf, drpcFrame{kind: drpcKindInvoke, done: true, stream: stream, message: 1, data: []byte(rpc)})
+	buf = appendDrpcFrame(buf, drpcFrame{kind: drpcKindMessage, done: true, stream: stream, message: 2, data: request})
+	buf = appendDrpcFrame(buf, drpcFrame{kind: drpcKindCloseSend, done: true, stream: stream, message: 3})
+	if _, err := conn.Write(buf); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	var packet []byte
+	for {
+		fr, err := readDrpcFrame(reader)
+		if err != nil {
+			return nil, fmt.Errorf("reading response for %s: %w", rpc, err)
+		}
+		if fr.control || fr.stream != stream {
+			continue
+		}
+
+		// Packets may be split across several frames, the last one is marked as done.
+		packet = append(packet, fr.data...)
+		if !fr.done {
+			continue
+		}
+
+		switch fr.kind {
+		case drpcKindMessage:
+			return packet, nil
+		case drpcKindError:
+			return nil, drpcError(packet)
+		case drpcKindClose:
+			return nil, fmt.Errorf("stream for %s closed without a response", rpc)
+		}
+		packet = nil
+	}
+}
+
+// drpcError decodes an error packet, which is an 8 byte error code followed by the message.
+func drpcError(data []byte) error {
+	if len(data) < 8 {
+		return errors.New(string(data))
+	}
+	code := binary.BigEndian.Uint64(data[:8])
+	if code == 0 {
+		return errors.New(string(data[8:]))
+	}
+	return fmt.Errorf("%s (code %d)", data[8:], code)
+}
+
+// protoFields calls fn for every field of an encoded protobuf message. Varint and fixed
+// width fields are passed as v, length-delimited fields as b; groups are skipped.
+func protoFields(data []byte, fn func(num protowire.Number, v uint64, b []byte)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		switch typ {
+		case protowire.VarintType:
+			var v uint64
+			v, n = protowire.ConsumeVarint(data)
+			if n >= 0 {
+				fn(num, v, nil)
+			}
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(data)
+			if n >= 0 {
+				fn(num, uint64(v), nil)
+			}
+		case protowire.Fixed64Type:
+			var v uint64
+			v, n = protowire.ConsumeFixed64(data)
+			if n >= 0 {
+				fn(num, v, nil)
+			}
+		case protowire.BytesType:
+			var b []byte
+			b, n = protowire.ConsumeBytes(data)
+			if n >= 0 {
+				fn(num, 0, b)
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+// protoTime decodes a google.protobuf.Timestamp or google.protobuf.Duration message.
+func protoTime(data []byte) (seconds int64, nanos int32, err error) {
+	err = protoFields(data, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			seconds = int64(v)
+		case 2:
+			nanos = int32(v)
+		}
+	})
+	return seconds, nanos, err
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// MultinodeClient talks to the API of a Storj multinode dashboard, which holds the API keys of the
+// nodes it manages and queries them on our behalf.
+type MultinodeClient struct {
+	BaseURL    string
+	httpClient *http.Client
+}
+
+func NewMultinodeClient(baseURL string) *MultinodeClient {
+	return &MultinodeClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			// The multinode dashboard contacts every node before answering.
+			Timeout: time.Second * 30,
+		},
+	}
+}
+
+func (c *MultinodeClient) get(endpoint string, target interface{}) error {
+	resp, err := c.httpClient.Get(c.BaseURL + endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Multinode API request failed with status code: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (c *MultinodeClient) Nodes() ([]models.MultinodeNode, error) {
+	var data []models.MultinodeNode
+	if err := c.get("/api/v0/nodes/infos", &data); err != nil {
+		return nil, fmt.Errorf("Multinode API request for nodes failed: %w", err)
+	}
+	return data, nil
+}
+
+func (c *MultinodeClient) PayoutSummary() (models.MultinodePayoutSummary, error) {
+	var data models.MultinodePayoutSummary
+	if err := c.get("/api/v0/payouts/summaries", &data); err != nil {
+		return data, fmt.Errorf("Multinode API request for payout summaries failed: %w", err)
+	}
+	return data, nil
+}
+
+func (c *MultinodeClient) DiskSpace(nodeID string) (models.MultinodeDiskSpace, error) {
+	var data models.MultinodeDiskSpace
+	if err := c.get("/api/v0/storage/disk-space/"+url.PathEscape(nodeID), &data); err != nil {
+		return data, fmt.Errorf("Multinode API request for disk space of node %s failed: %w", nodeID, err)
+	}
+	return data, nil
+}
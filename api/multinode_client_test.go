@@ -0,0 +1,70 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newMultinodeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v0/nodes/infos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": "1node", "name": "first", "version": "v1.104.5", "lastContact": "2024-07-01T12:00:00Z", "diskSpaceUsed": 100, "diskSpaceLeft": 900, "bandwidthUsed": 50, "totalEarned": 1234, "status": "online"},
+			{"id": "2node", "name": "second", "version": "v1.104.5", "lastContact": "0001-01-01T00:00:00Z", "status": "offline"}
+		]`)
+	})
+	mux.HandleFunc("/api/v0/payouts/summaries", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"totalEarned": 1234, "totalHeld": 100, "totalPaid": 1000, "nodeSummary": [{"nodeId": "1node", "nodeName": "first", "held": 100, "paid": 1000}]}`)
+	})
+	mux.HandleFunc("/api/v0/storage/disk-space/1node", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"allocated": 1000, "usedPieces": 80, "usedTrash": 20, "free": 5000, "available": 900, "overused": 0}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMultinodeClient(t *testing.T) {
+	client := NewMultinodeClient(newMultinodeServer(t).URL + "/")
+
+	nodes, err := client.Nodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("got %d nodes, want 2", len(nodes))
+	}
+	first := nodes[0]
+	if first.ID != "1node" || first.Name != "first" || first.Status != "online" || first.TotalEarned != 1234 || first.BandwidthUsed != 50 {
+		t.Errorf("first node = %+v", first)
+	}
+	if !first.LastContact.Equal(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastContact = %v", first.LastContact)
+	}
+
+	summary, err := client.PayoutSummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.NodeSummary) != 1 || summary.NodeSummary[0].NodeID != "1node" || summary.NodeSummary[0].Held != 100 || summary.NodeSummary[0].Paid != 1000 {
+		t.Errorf("payout summary = %+v", summary)
+	}
+
+	diskSpace, err := client.DiskSpace("1node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diskSpace.Allocated != 1000 || diskSpace.UsedPieces != 80 || diskSpace.UsedTrash != 20 || diskSpace.Available != 900 {
+		t.Errorf("disk space = %+v", diskSpace)
+	}
+
+	if _, err := client.DiskSpace("unknown"); err == nil {
+		t.Error("expected an error for an unknown node")
+	}
+}
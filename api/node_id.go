@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+// Storj node IDs are 32 bytes whose last byte holds the ID version. Their string form is
+// the base58 check encoding of the unversioned ID, prefixed with the version byte.
+const (
+	nodeIDSize     = sha256.Size
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// EncodeNodeID returns the string form of a raw node ID.
+func EncodeNodeID(id []byte) string {
+	if len(id) != nodeIDSize {
+		return ""
+	}
+	payload := make([]byte, 0, 1+nodeIDSize+4)
+	payload = append(payload, id[nodeIDSize-1])
+	payload = append(payload, id[:nodeIDSize-1]...)
+	payload = append(payload, 0)
+	checksum := doubleSHA256(payload)
+	return base58Encode(append(payload, checksum[:4]...))
+}
+
+// DecodeNodeID parses the string form of a node ID into its raw bytes.
+func DecodeNodeID(s string) ([]byte, error) {
+	decoded, err := base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) != 1+nodeIDSize+4 {
+		return nil, errors.New("invalid node ID length")
+	}
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	expected := doubleSHA256(payload)
+	if !bytes.Equal(checksum, expected[:4]) {
+		return nil, errors.New("invalid node ID checksum")
+	}
+	id := make([]byte, nodeIDSize)
+	copy(id, payload[1:nodeIDSize])
+	id[nodeIDSize-1] = payload[0]
+	return id, nil
+}
+
+func doubleSHA256(data []byte) [sha256.Size]byte {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
+
+func base58Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for x.Sign() > 0 {
+		x.DivMod(x, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		index := bytes.IndexRune([]byte(base58Alphabet), r)
+		if index < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		x.Mul(x, radix)
+		x.Add(x, big.NewInt(int64(index)))
+	}
+
+	decoded := x.Bytes()
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), decoded...), nil
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+const DefaultNUTPort = "3493"
+
+// NUTClient reads UPS variables from a Network UPS Tools server (upsd) using its line based protocol.
+type NUTClient struct {
+	Address  string
+	UPS      string
+	username string
+	password string
+	timeout  time.Duration
+}
+
+func NewNUTClient(address, ups, username, password string) *NUTClient {
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, DefaultNUTPort)
+	}
+	return &NUTClient{
+		Address:  address,
+		UPS:      ups,
+		username: username,
+		password: password,
+		timeout:  time.Second * 10,
+	}
+}
+
+// Variables returns all variables of the UPS, such as "ups.status" or "battery.charge".
+func (c *NUTClient) Variables() (map[string]string, error) {
+	conn, err := net.DialTimeout("tcp", c.Address, c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	command := func(line string) (string, error) {
+		if _, err := fmt.Fprintf(conn, "%s\n", line); err != nil {
+			return "", err
+		}
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		response = strings.TrimRight(response, "\r\n")
+		if strings.HasPrefix(response, "ERR ") {
+			return "", errors.New(strings.TrimPrefix(response, "ERR "))
+		}
+		return response, nil
+	}
+
+	if c.username != "" {
+		if _, err := command("USERNAME " + c.username); err != nil {
+			return nil, fmt.Errorf("NUT login failed: %w", err)
+		}
+		if _, err := command("PASSWORD " + c.password); err != nil {
+			return nil, fmt.Errorf("NUT login failed: %w", err)
+		}
+	}
+
+	if _, err := command("LIST VAR " + c.UPS); err != nil {
+		return nil, fmt.Errorf("NUT request for variables of UPS %s failed: %w", c.UPS, err)
+	}
+	variables := make(map[string]string)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "END LIST VAR") {
+			break
+		}
+		// VAR <ups> <name> "<value>"
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) != 4 || fields[0] != "VAR" {
+			continue
+		}
+		variables[fields[2]] = unquoteNUT(fields[3])
+	}
+
+	fmt.Fprintf(conn, "LOGOUT\n")
+	return variables, nil
+}
+
+// Status returns the state of the UPS.
+func (c *NUTClient) Status() (models.UPSStatus, error) {
+	status := models.UPSStatus{Name: c.UPS}
+	variables, err := c.Variables()
+	if err != nil {
+		return status, err
+	}
+
+	status.Status = variables["ups.status"]
+	for _, flag := range strings.Fields(status.Status) {
+		switch flag {
+		case "OB":
+			status.OnBattery = true
+		case "LB":
+			status.LowBattery = true
+		case "CHRG":
+			status.Charging = true
+		}
+	}
+
+	number := func(name string) *float64 {
+		value, err := strconv.ParseFloat(variables[name], 64)
+		if err != nil {
+			return nil
+		}
+		return &value
+	}
+	status.BatteryCharge = number("battery.charge")
+	status.BatteryRuntime = number("battery.runtime")
+	status.Load = number("ups.load")
+	return status, nil
+}
+
+// unquoteNUT removes the quotes around a NUT value and its backslash escapes.
+func unquoteNUT(value string) string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+	var unquoted strings.Builder
+	escaped := false
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		unquoted.WriteRune(r)
+	}
+	return unquoted.String()
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+)
+
+// serveNUT answers the commands of a single NUT client connection with the responses of a fake upsd.
+func serveNUT(t *testing.T, respond func(command string) []string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			for _, line := range respond(scanner.Text()) {
+				fmt.Fprintf(conn, "%s\n", line)
+			}
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestNUTClientStatus(t *testing.T) {
+	commands := make(chan string, 10)
+	address := serveNUT(t, func(command string) []string {
+		commands <- command
+		switch command {
+		case "USERNAME monitor", "PASSWORD secret", "LOGOUT":
+			return []string{"OK"}
+		case "LIST VAR myups":
+			return []string{
+				"BEGIN LIST VAR myups",
+				`VAR myups ups.status "OB LB CHRG"`,
+				`VAR myups battery.charge "42"`,
+				`VAR myups battery.runtime "300"`,
+				`VAR myups ups.model "Back-UPS \"ES\" 700"`,
+				"END LIST VAR myups",
+			}
+		}
+		return []string{"ERR UNKNOWN-COMMAND"}
+	})
+
+	client := NewNUTClient(address, "myups", "monitor", "secret")
+	variables, err := client.Variables()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model := variables["ups.model"]; model != `Back-UPS "ES" 700` {
+		t.Errorf("ups.model = %q", model)
+	}
+	if got := strings.Join([]string{<-commands, <-commands, <-commands}, ","); got != "USERNAME monitor,PASSWORD secret,LIST VAR myups" {
+		t.Errorf("commands = %q", got)
+	}
+
+	status, err := NewNUTClient(serveNUT(t, func(command string) []string {
+		if command == "LIST VAR myups" {
+			return []string{"BEGIN LIST VAR myups", `VAR myups ups.status "OB LB CHRG"`, `VAR myups battery.charge "42"`, "END LIST VAR myups"}
+		}
+		return []string{"OK"}
+	}), "myups", "", "").Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.OnBattery || !status.LowBattery || !status.Charging {
+		t.Errorf("status flags = %+v", status)
+	}
+	if status.BatteryCharge == nil || *status.BatteryCharge != 42 {
+		t.Errorf("BatteryCharge = %v", status.BatteryCharge)
+	}
+	if status.BatteryRuntime != nil || status.Load != nil {
+		t.Errorf("missing variables should be nil, got runtime %v and load %v", status.BatteryRuntime, status.Load)
+	}
+}
+
+func TestNUTClientError(t *testing.T) {
+	address := serveNUT(t, func(command string) []string {
+		if strings.HasPrefix(command, "LIST VAR") {
+			return []string{"ERR UNKNOWN-UPS"}
+		}
+		return []string{"OK"}
+	})
+
+	_, err := NewNUTClient(address, "missing", "", "").Variables()
+	if err == nil || !strings.Contains(err.Error(), "UNKNOWN-UPS") {
+		t.Errorf("expected UNKNOWN-UPS error, got %v", err)
+	}
+}
+
+func TestNUTClientLoginError(t *testing.T) {
+	address := serveNUT(t, func(command string) []string {
+		if strings.HasPrefix(command, "PASSWORD") {
+			return []string{"ERR ACCESS-DENIED"}
+		}
+		return []string{"OK"}
+	})
+
+	_, err := NewNUTClient(address, "myups", "monitor", "wrong").Variables()
+	if err == nil || !strings.Contains(err.Error(), "ACCESS-DENIED") {
+		t.Errorf("expected ACCESS-DENIED error, got %v", err)
+	}
+}
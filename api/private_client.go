@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	rpcDashboard       = "/storagenode.inspector.PieceStoreInspector/Dashboard"
+	rpcGetExitProgress = "/storagenode.gracefulexit.NodeGracefulExit/GetExitProgress"
+)
+
+// PrivateClient talks to the storagenode private address (server.private-address),
+// the same endpoint used by `storagenode dashboard` and `storagenode exit-satellite`.
+type PrivateClient struct {
+	Address string
+	timeout time.Duration
+}
+
+func NewPrivateClient(address string) *PrivateClient {
+	return &PrivateClient{
+		Address: address,
+		timeout: time.Second * 10,
+	}
+}
+
+func (c *PrivateClient) invoke(rpc string, request []byte) ([]byte, error) {
+	return drpcInvoke(c.Address, c.timeout, rpc, request)
+}
+
+func (c *PrivateClient) Dashboard() (models.DashboardData, error) {
+	var data models.DashboardData
+	response, err := c.invoke(rpcDashboard, nil)
+	if err != nil {
+		return data, fmt.Errorf("Private API request for dashboard data failed: %w", err)
+	}
+
+	var stats []byte
+	err = protoFields(response, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			data.NodeID = b
+		case 2:
+			data.NodeConnections = int64(v)
+		case 4:
+			data.InternalAddress = string(b)
+		case 5:
+			data.ExternalAddress = string(b)
+		case 6:
+			data.DashboardAddress = string(b)
+		case 7:
+			stats = b
+		case 8:
+			seconds, nanos, _ := protoTime(b)
+			data.Uptime = time.Duration(seconds)*time.Second + time.Duration(nanos)
+		case 9:
+			seconds, nanos, _ := protoTime(b)
+			data.LastPinged = time.Unix(seconds, int64(nanos))
+		}
+	})
+	if err == nil {
+		err = protoFields(stats, func(num protowire.Number, v uint64, b []byte) {
+			switch num {
+			case 1:
+				data.Stats.UsedSpace = int64(v)
+			case 2:
+				data.Stats.AvailableSpace = int64(v)
+			case 3:
+				data.Stats.UsedIngress = int64(v)
+			case 4:
+				data.Stats.UsedEgress = int64(v)
+			case 5:
+				data.Stats.UsedBandwidth = int64(v)
+			case 6:
+				data.Stats.AvailableBandwidth = int64(v)
+			}
+		})
+	}
+	if err != nil {
+		return data, fmt.Errorf("Private API response for dashboard data could not be decoded: %w", err)
+	}
+	return data, nil
+}
+
+// ExitProgress returns the graceful exit progress for every satellite the node has started exiting.
+func (c *PrivateClient) ExitProgress() ([]models.ExitProgress, error) {
+	response, err := c.invoke(rpcGetExitProgress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Private API request for graceful exit progress failed: %w", err)
+	}
+
+	var progress []models.ExitProgress
+	var decodeErr error
+	err = protoFields(response, func(num protowire.Number, v uint64, b []byte) {
+		if num == 1 && decodeErr == nil {
+			var p models.ExitProgress
+			p, decodeErr = decodeExitProgress(b)
+			progress = append(progress, p)
+		}
+	})
+	if err == nil {
+		err = decodeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Private API response for graceful exit progress could not be decoded: %w", err)
+	}
+	return progress, nil
+}
+
+func decodeExitProgress(data []byte) (models.ExitProgress, error) {
+	var progress models.ExitProgress
+	var receipt []byte
+	err := protoFields(data, func(num protowire.Number, v uint64, b []byte) {
+		switch num {
+		case 1:
+			progress.DomainName = string(b)
+		case 2:
+			progress.SatelliteID = EncodeNodeID(b)
+		case 3:
+			progress.PercentComplete = float64(math.Float32frombits(uint32(v)))
+		case 4:
+			progress.Successful = v != 0
+		case 5:
+			receipt = b
+		}
+	})
+	if err != nil || receipt == nil {
+		return progress, err
+	}
+
+	// The receipt is an ExitCompleted message (completed is field 4) on success and an ExitFailed message (failed is field 5) otherwise.
+	timeField := protowire.Number(5)
+	if progress.Successful {
+		timeField = 4
+	}
+	var finished []byte
+	err = protoFields(receipt, func(num protowire.Number, v uint64, b []byte) {
+		if num == timeField {
+			finished = b
+		}
+	})
+	if err != nil || finished == nil {
+		return progress, err
+	}
+
+	seconds, nanos, err := protoTime(finished)
+	if err != nil {
+		return progress, err
+	}
+	progress.FinishedAt = time.Unix(seconds, int64(nanos))
+	return progress, nil
+}
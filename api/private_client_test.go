@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// serveDrpc accepts a single connection on a local listener and answers the unary call with response.
+// The rpc name the client invoked is sent on the returned channel.
+func serveDrpc(t *testing.T, response []byte) (string, <-chan string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	invoked := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		header := make([]byte, len(drpcHeader))
+		if _, err := io.ReadFull(reader, header); err != nil || string(header) != drpcHeader {
+			invoked <- "missing header: " + string(header)
+			return
+		}
+
+		var stream uint64
+		for {
+			fr, err := readDrpcFrame(reader)
+			if err != nil {
+				return
+			}
+			stream = fr.stream
+			if fr.kind == drpcKindInvoke {
+				invoked <- string(fr.data)
+			}
+			if fr.kind == drpcKindCloseSend {
+				break
+			}
+		}
+
+		var buf []byte
+		buf = appendDrpcFrame(buf, drpcFrame{kind: drpcKindMessage, done: true, stream: stream, message: 1, data: response})
+		buf = appendDrpcFrame(buf, drpcFrame{kind: drpcKindClose, done: true, stream: stream, message: 2})
+		_, _ = conn.Write(buf)
+	}()
+
+	return listener.Addr().String(), invoked
+}
+
+func appendProtoTime(b []byte, num protowire.Number, seconds int64, nanos int32) []byte {
+	var msg []byte
+	msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(seconds))
+	msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(nanos))
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func TestPrivateClientDashboard(t *testing.T) {
+	var stats []byte
+	for num, value := range []uint64{1: 1000, 2: 2000, 3: 300, 4: 400, 5: 700, 6: 5000} {
+		if num == 0 {
+			continue
+		}
+		stats = protowire.AppendTag(stats, protowire.Number(num), protowire.VarintType)
+		stats = protowire.AppendVarint(stats, value)
+	}
+
+	var response []byte
+	response = protowire.AppendTag(response, 1, protowire.BytesType)
+	response = protowire.AppendBytes(response, []byte{0x01, 0x02, 0x03})
+	response = protowire.AppendTag(response, 2, protowire.VarintType)
+	response = protowire.AppendVarint(response, 42)
+	response = protowire.AppendTag(response, 4, protowire.BytesType)
+	response = protowire.AppendString(response, "172.17.0.2:28967")
+	response = protowire.AppendTag(response, 5, protowire.BytesType)
+	response = protowire.AppendString(response, "node.example.com:28967")
+	response = protowire.AppendTag(response, 6, protowire.BytesType)
+	response = protowire.AppendString(response, "127.0.0.1:14002")
+	response = protowire.AppendTag(response, 7, protowire.BytesType)
+	response = protowire.AppendBytes(response, stats)
+	response = appendProtoTime(response, 8, 3600, 500)
+	response = appendProtoTime(response, 9, 1700000000, 0)
+
+	address, invoked := serveDrpc(t, response)
+	data, err := NewPrivateClient(address).Dashboard()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rpc := <-invoked; rpc != rpcDashboard {
+		t.Errorf("invoked %q, want %q", rpc, rpcDashboard)
+	}
+	if string(data.NodeID) != "\x01\x02\x03" {
+		t.Errorf("NodeID = %x", data.NodeID)
+	}
+	if data.NodeConnections != 42 {
+		t.Errorf("NodeConnections = %d, want 42", data.NodeConnections)
+	}
+	if data.InternalAddress != "172.17.0.2:28967" || data.ExternalAddress != "node.example.com:28967" || data.DashboardAddress != "127.0.0.1:14002" {
+		t.Errorf("addresses = %q, %q, %q", data.InternalAddress, data.ExternalAddress, data.DashboardAddress)
+	}
+	if data.Stats.UsedSpace != 1000 || data.Stats.AvailableSpace != 2000 || data.Stats.UsedIngress != 300 ||
+		data.Stats.UsedEgress != 400 || data.Stats.UsedBandwidth != 700 || data.Stats.AvailableBandwidth != 5000 {
+		t.Errorf("Stats = %+v", data.Stats)
+	}
+	if data.Uptime != time.Hour+500 {
+		t.Errorf("Uptime = %v, want %v", data.Uptime, time.Hour+500)
+	}
+	if !data.LastPinged.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("LastPinged = %v", data.LastPinged)
+	}
+}
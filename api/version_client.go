@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+const DefaultVersionURL = "https://version.storj.io"
+
+// VersionClient reads the release information from a version.storj.io compatible version server.
+type VersionClient struct {
+	URL        string
+	httpClient *http.Client
+}
+
+func NewVersionClient(url string) *VersionClient {
+	return &VersionClient{
+		URL: url,
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+type versionResponse struct {
+	Processes struct {
+		Storagenode struct {
+			Minimum struct {
+				Version string `json:"version"`
+			} `json:"minimum"`
+			Suggested struct {
+				Version string `json:"version"`
+			} `json:"suggested"`
+			Rollout struct {
+				Seed   string `json:"seed"`
+				Cursor string `json:"cursor"`
+			} `json:"rollout"`
+		} `json:"storagenode"`
+	} `json:"processes"`
+}
+
+func (c *VersionClient) Storagenode() (models.StoragenodeVersions, error) {
+	var versions models.StoragenodeVersions
+
+	resp, err := c.httpClient.Get(c.URL)
+	if err != nil {
+		return versions, fmt.Errorf("Version server request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return versions, fmt.Errorf("Version server request failed with status code: %d", resp.StatusCode)
+	}
+
+	var data versionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return versions, fmt.Errorf("Version server response invalid: %w", err)
+	}
+
+	storagenode := data.Processes.Storagenode
+	versions.Minimum = storagenode.Minimum.Version
+	versions.Suggested = storagenode.Suggested.Version
+	if versions.RolloutSeed, err = hex.DecodeString(storagenode.Rollout.Seed); err != nil {
+		return versions, fmt.Errorf("Version server rollout seed invalid: %w", err)
+	}
+	if versions.RolloutCursor, err = hex.DecodeString(storagenode.Rollout.Cursor); err != nil {
+		return versions, fmt.Errorf("Version server rollout cursor invalid: %w", err)
+	}
+	return versions, nil
+}
+
+// RolloutPosition returns the position of a node in the rollout of the suggested version,
+// the HMAC of the node ID keyed with the rollout seed. The node is eligible once the cursor reaches it.
+func RolloutPosition(nodeID string, seed []byte) ([]byte, error) {
+	id, err := DecodeNodeID(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	hash := hmac.New(sha256.New, seed)
+	hash.Write(id)
+	return hash.Sum(nil), nil
+}
+
+// RolloutEligible reports if the rollout cursor has reached the position of the node.
+func RolloutEligible(position, cursor []byte) bool {
+	return bytes.Compare(position, cursor) <= 0
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nodeConfig holds the settings of a single node, read from STORJ_NODE_%d_* environment variables.
+type nodeConfig struct {
+	URL             string
+	Name            string
+	PrivateAddress  string
+	DebugURL        string
+	LogFile         string
+	LogContainer    string
+	StoragePath     string
+	DBPath          string
+	ConfigPath      string
+	IdentityPath    string
+	ExternalAddress string
+	Process         string
+	Container       string
+	DiskDevices     []string
+	SmartFiles      []string
+	PowerMeterURL   string
+}
+
+func getNodeConfigs() []nodeConfig {
+	var nodes []nodeConfig
+	for i := 1; ; i++ {
+		NodeURL := nodeEnv(i, "URL")
+		if NodeURL == "" {
+			break
+		}
+		url, err := url.Parse(NodeURL)
+		if err != nil {
+			log.Printf("Error parsing URL for node %d, %s: %v", i, NodeURL, err)
+			continue
+		}
+		// The storagenode keeps its databases in the storage directory unless storage2.database-dir is set.
+		dbPath := nodeEnv(i, "DB_PATH")
+		if dbPath == "" {
+			dbPath = nodeEnv(i, "STORAGE_PATH")
+		}
+		name := nodeEnv(i, "NAME")
+		if name == "" {
+			name = url.Host
+		}
+		nodes = append(nodes, nodeConfig{
+			URL:             url.String(),
+			Name:            name,
+			PrivateAddress:  nodeEnv(i, "PRIVATE_ADDRESS"),
+			DebugURL:        nodeEnv(i, "DEBUG_URL"),
+			LogFile:         nodeEnv(i, "LOG_FILE"),
+			LogContainer:    nodeEnv(i, "LOG_CONTAINER"),
+			StoragePath:     nodeEnv(i, "STORAGE_PATH"),
+			DBPath:          dbPath,
+			ConfigPath:      nodeEnv(i, "CONFIG_PATH"),
+			IdentityPath:    nodeEnv(i, "IDENTITY_PATH"),
+			ExternalAddress: nodeEnv(i, "EXTERNAL_ADDRESS"),
+			Process:         nodeEnv(i, "PROCESS"),
+			Container:       nodeEnv(i, "CONTAINER"),
+			DiskDevices:     splitList(nodeEnv(i, "DISK_DEVICES")),
+			SmartFiles:      splitList(nodeEnv(i, "SMART_FILES")),
+			PowerMeterURL:   nodeEnv(i, "POWER_METER_URL"),
+		})
+	}
+	return nodes
+}
+
+// powerMeterConfig is a power meter shared by several nodes, read from POWER_METER_%d_* environment variables.
+type powerMeterConfig struct {
+	Name string
+	URL  string
+	// Nodes are the numbers of the STORJ_NODE_%d_* nodes on the meter, all nodes if empty.
+	Nodes []int
+}
+
+func getPowerMeterConfigs() []powerMeterConfig {
+	var meters []powerMeterConfig
+	for i := 1; ; i++ {
+		meterURL := os.Getenv(fmt.Sprintf("POWER_METER_%d_URL", i))
+		if meterURL == "" {
+			break
+		}
+		// The host alone is not unique, e.g. for the channels of a Shelly with several relays.
+		name := os.Getenv(fmt.Sprintf("POWER_METER_%d_NAME", i))
+		if name == "" {
+			name = meterURL
+		}
+		var nodes []int
+		for _, node := range splitList(os.Getenv(fmt.Sprintf("POWER_METER_%d_NODES", i))) {
+			number, err := strconv.Atoi(node)
+			if err != nil || number < 1 {
+				log.Fatalf("Invalid node number in POWER_METER_%d_NODES: %s\n", i, node)
+			}
+			nodes = append(nodes, number)
+		}
+		meters = append(meters, powerMeterConfig{Name: name, URL: meterURL, Nodes: nodes})
+	}
+	return meters
+}
+
+func nodeEnv(i int, name string) string {
+	return os.Getenv(fmt.Sprintf("STORJ_NODE_%d_%s", i, name))
+}
+
+// splitList splits a comma separated list, ignoring empty entries.
+func splitList(value string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// getDurationEnv reads a duration such as "30m" or "12h" from the environment.
+func getDurationEnv(name string, defaultValue time.Duration) time.Duration {
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Fatalf("Invalid duration in %s: %s\n", name, value)
+	}
+	return duration
+}
+
+// getIntEnv reads a non-negative number from the environment.
+func getIntEnv(name string, defaultValue int) int {
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return defaultValue
+	}
+	intValue, err := strconv.Atoi(value)
+	if err != nil || intValue < 0 {
+		log.Fatalf("Invalid number in %s: %s\n", name, value)
+	}
+	return intValue
+}
+
+// getBoolEnv reports if a feature flag such as "true" or "1" is set in the environment.
+func getBoolEnv(name string) bool {
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatalf("Invalid boolean in %s: %s\n", name, value)
+	}
+	return enabled
+}
@@ -4,32 +4,352 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/akash329d/storj_exporter/api"
 	"github.com/akash329d/storj_exporter/collectors"
+	"github.com/akash329d/storj_exporter/costs"
+	"github.com/akash329d/storj_exporter/logs"
+	"github.com/akash329d/storj_exporter/nodeconfig"
+	"github.com/akash329d/storj_exporter/nodedb"
+	"github.com/akash329d/storj_exporter/power"
+	"github.com/akash329d/storj_exporter/probe"
+	"github.com/akash329d/storj_exporter/smart"
+	"github.com/akash329d/storj_exporter/storage"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func Run() {
-	nodeURLs := getNodeURLs()
-	if len(nodeURLs) == 0 {
-		log.Fatal("No Storj node URLs found in environment variables.")
+	nodes := getNodeConfigs()
+	multinodeURL := os.Getenv("MULTINODE_URL")
+	if len(nodes) == 0 && multinodeURL == "" {
+		log.Fatal("No Storj node URLs or multinode dashboard URL found in environment variables.")
 	}
 
-	clients := make([]*api.ApiClient, len(nodeURLs))
-	for i, url := range nodeURLs {
-		clients[i] = api.NewApiClient(url)
+	clients := make([]*api.ApiClient, len(nodes))
+	for i, node := range nodes {
+		clients[i] = api.NewApiClient(node.URL)
 	}
 	
-	prometheus.MustRegister(collectors.NewNodeCollector(clients))
+	if multinodeURL != "" {
+		prometheus.MustRegister(collectors.NewMultinodeCollector(api.NewMultinodeClient(multinodeURL)))
+	}
+
+	// The node collector is registered once its observers are added.
+	nodeCollector := collectors.NewNodeCollector(clients)
 	prometheus.MustRegister(collectors.NewSatelliteCollector(clients))
 	prometheus.MustRegister(collectors.NewPayoutCollector(clients))
 
+	if getBoolEnv("VERSION_CHECK_ENABLED") {
+		versionURL := api.DefaultVersionURL
+		if value, exists := os.LookupEnv("VERSION_URL"); exists {
+			versionURL = value
+		}
+		versionCollector := collectors.NewVersionCollector(api.NewVersionClient(versionURL), getDurationEnv("VERSION_REFRESH_INTERVAL", time.Minute*15))
+		nodeCollector.Observe(versionCollector)
+		prometheus.MustRegister(versionCollector)
+	}
+
+	notificationCollector := collectors.NewNotificationCollector(clients)
+	prometheus.MustRegister(notificationCollector)
+
+	var privateTargets []collectors.PrivateTarget
+	for i, node := range nodes {
+		if node.PrivateAddress != "" {
+			privateTargets = append(privateTargets, collectors.PrivateTarget{NodeID: clients[i].NodeID, Client: api.NewPrivateClient(node.PrivateAddress)})
+		}
+	}
+	if len(privateTargets) > 0 {
+		prometheus.MustRegister(collectors.NewPrivateCollector(privateTargets))
+		prometheus.MustRegister(collectors.NewGracefulExitCollector(privateTargets))
+	}
+
+	var reachabilityTargets []collectors.ReachabilityTarget
+	if getBoolEnv("REACHABILITY_PROBE_ENABLED") {
+		for i, node := range nodes {
+			address := node.ExternalAddress
+			if address == "" && node.ConfigPath != "" {
+				if config, err := nodeconfig.Load(node.ConfigPath); err == nil {
+					address = config.ExternalAddress
+				}
+			}
+			if address == "" && node.PrivateAddress == "" {
+				log.Printf("No external address known for node %s, skipping reachability probes", node.URL)
+				continue
+			}
+			target := collectors.ReachabilityTarget{NodeID: clients[i].NodeID, Address: address}
+			if address == "" {
+				target.Private = api.NewPrivateClient(node.PrivateAddress)
+			}
+			reachabilityTargets = append(reachabilityTargets, target)
+		}
+	}
+	if len(reachabilityTargets) > 0 {
+		prometheus.MustRegister(collectors.NewReachabilityCollector(reachabilityTargets, getDurationEnv("REACHABILITY_PROBE_TIMEOUT", time.Second*5)))
+	}
+
+	if getBoolEnv("SATELLITE_PROBE_ENABLED") {
+		satellites := make(map[string]string)
+		for _, client := range clients {
+			for _, satellite := range client.Satellites {
+				satellites[satellite.ID] = probe.SatelliteAddress(satellite.URL)
+			}
+		}
+		prober := probe.NewSatelliteProber(satellites, getDurationEnv("SATELLITE_PROBE_INTERVAL", time.Minute), getDurationEnv("SATELLITE_PROBE_TIMEOUT", time.Second*5))
+		go prober.Run()
+		prometheus.MustRegister(collectors.NewSatelliteProbeCollector(prober))
+	}
+
+	if address := os.Getenv("NUT_ADDRESS"); address != "" {
+		upsName := "ups"
+		if value, exists := os.LookupEnv("NUT_UPS"); exists {
+			upsName = value
+		}
+		nutClient := api.NewNUTClient(address, upsName, os.Getenv("NUT_USERNAME"), os.Getenv("NUT_PASSWORD"))
+		upsCollector := collectors.NewUPSCollector(nutClient, getDurationEnv("UPS_POWER_EVENT_WINDOW", time.Minute*15))
+		nodeCollector.Observe(upsCollector)
+		prometheus.MustRegister(upsCollector)
+	}
+
+	var debugTargets []collectors.DebugTarget
+	for i, node := range nodes {
+		if node.DebugURL != "" {
+			debugTargets = append(debugTargets, collectors.DebugTarget{NodeID: clients[i].NodeID, NodeName: node.Name, Client: api.NewDebugClient(node.DebugURL)})
+		}
+	}
+	if len(debugTargets) > 0 {
+		allowlist := collectors.DefaultDebugAllowlist
+		if value, exists := os.LookupEnv("DEBUG_METRICS_ALLOWLIST"); exists {
+			allowlist = value
+		}
+		allowlistRegexp, err := regexp.Compile(allowlist)
+		if err != nil {
+			log.Fatalf("Invalid regular expression in DEBUG_METRICS_ALLOWLIST: %v\n", err)
+		}
+		prometheus.MustRegister(collectors.NewDebugCollector(debugTargets, allowlistRegexp))
+	}
+
+	storageScanRate := getIntEnv("STORAGE_SCAN_RATE", 1000)
+
+	var storageTargets []collectors.StorageTarget
+	for i, node := range nodes {
+		if node.StoragePath != "" {
+			scanner := storage.NewScanner(node.StoragePath, getDurationEnv("STORAGE_SCAN_INTERVAL", time.Hour*12), storageScanRate)
+			go scanner.Run()
+			storageTargets = append(storageTargets, collectors.StorageTarget{NodeID: clients[i].NodeID, Scanner: scanner})
+		}
+	}
+	if len(storageTargets) > 0 {
+		prometheus.MustRegister(collectors.NewStorageCollector(storageTargets))
+	}
+
+	var configTargets []collectors.ConfigTarget
+	for i, node := range nodes {
+		if node.ConfigPath != "" {
+			target := collectors.ConfigTarget{Client: clients[i], ConfigPath: node.ConfigPath, StoragePath: node.StoragePath}
+			if node.PrivateAddress != "" {
+				target.Private = api.NewPrivateClient(node.PrivateAddress)
+			}
+			configTargets = append(configTargets, target)
+		}
+	}
+	if len(configTargets) > 0 {
+		configCollector := collectors.NewConfigCollector(configTargets)
+		nodeCollector.Observe(configCollector)
+		prometheus.MustRegister(configCollector)
+	}
+	prometheus.MustRegister(nodeCollector)
+
+	var identityTargets []collectors.IdentityTarget
+	for i, node := range nodes {
+		if node.IdentityPath != "" {
+			identityTargets = append(identityTargets, collectors.IdentityTarget{NodeID: clients[i].NodeID, Dir: node.IdentityPath})
+		}
+	}
+	if len(identityTargets) > 0 {
+		prometheus.MustRegister(collectors.NewIdentityCollector(identityTargets))
+	}
+
+	var processTargets []collectors.ProcessTarget
+	var processDockerClient *api.DockerClient
+	for i, node := range nodes {
+		switch {
+		case node.Container != "":
+			if processDockerClient == nil {
+				processDockerClient = newDockerClient()
+			}
+			processTargets = append(processTargets, collectors.ProcessTarget{NodeID: clients[i].NodeID, Container: node.Container, Docker: processDockerClient})
+		case node.Process != "":
+			processTargets = append(processTargets, collectors.ProcessTarget{NodeID: clients[i].NodeID, Process: node.Process})
+		}
+	}
+	if len(processTargets) > 0 {
+		prometheus.MustRegister(collectors.NewProcessCollector(processTargets))
+	}
+
+	var diskHealthTargets []collectors.DiskHealthTarget
+	var diskDevices []string
+	polled := make(map[string]bool)
+	for i, node := range nodes {
+		if len(node.DiskDevices) > 0 || len(node.SmartFiles) > 0 {
+			diskHealthTargets = append(diskHealthTargets, collectors.DiskHealthTarget{NodeID: clients[i].NodeID, Devices: node.DiskDevices, Files: node.SmartFiles})
+			// Nodes sharing a disk list the same device, it is only polled once.
+			for _, device := range node.DiskDevices {
+				if !polled[device] {
+					polled[device] = true
+					diskDevices = append(diskDevices, device)
+				}
+			}
+		}
+	}
+	if len(diskHealthTargets) > 0 {
+		smartctl := "smartctl"
+		if value, exists := os.LookupEnv("SMARTCTL_PATH"); exists {
+			smartctl = value
+		}
+		poller := smart.NewPoller(smartctl, diskDevices, getDurationEnv("SMARTCTL_INTERVAL", time.Minute*30))
+		if len(diskDevices) > 0 {
+			go poller.Run()
+		}
+		prometheus.MustRegister(collectors.NewDiskHealthCollector(diskHealthTargets, poller))
+	}
+
+	var powerTargets []collectors.PowerTarget
+	for i, node := range nodes {
+		if node.PowerMeterURL != "" {
+			powerTargets = append(powerTargets, collectors.PowerTarget{Meter: power.NewMeter(node.Name, node.PowerMeterURL), Clients: []*api.ApiClient{clients[i]}})
+		}
+	}
+	for _, meter := range getPowerMeterConfigs() {
+		meterClients := clients
+		if len(meter.Nodes) > 0 {
+			meterClients = nil
+			for _, number := range meter.Nodes {
+				if number > len(clients) {
+					log.Fatalf("Power meter %s refers to unknown node %d\n", meter.Name, number)
+				}
+				meterClients = append(meterClients, clients[number-1])
+			}
+		}
+		powerTargets = append(powerTargets, collectors.PowerTarget{Meter: power.NewMeter(meter.Name, meter.URL), Clients: meterClients})
+	}
+	meterNames := make(map[string]bool)
+	for _, target := range powerTargets {
+		if meterNames[target.Meter.Name] {
+			log.Fatalf("Power meter name %s is used more than once, set a unique POWER_METER_%%d_NAME or STORJ_NODE_%%d_NAME\n", target.Meter.Name)
+		}
+		meterNames[target.Meter.Name] = true
+	}
+	var powerCollector *collectors.PowerCollector
+	if len(powerTargets) > 0 {
+		powerCollector = collectors.NewPowerCollector(powerTargets)
+		prometheus.MustRegister(powerCollector)
+	}
+
+	if path := os.Getenv("COST_CONFIG"); path != "" {
+		costConfig, err := costs.Load(path)
+		if err != nil {
+			log.Fatalf("Error reading COST_CONFIG: %v\n", err)
+		}
+		var costTargets []collectors.CostTarget
+		configured := make(map[int]bool)
+		for _, nodeCosts := range costConfig.Nodes {
+			index := -1
+			for i, node := range nodes {
+				if (nodeCosts.NodeID != "" && nodeCosts.NodeID == clients[i].NodeID) || (nodeCosts.NodeID == "" && nodeCosts.Name == node.Name) {
+					index = i
+				}
+			}
+			if index < 0 {
+				log.Fatalf("Costs in COST_CONFIG refer to unknown node %s%s\n", nodeCosts.NodeID, nodeCosts.Name)
+			}
+			if configured[index] {
+				log.Fatalf("Costs in COST_CONFIG are configured twice for node %s\n", nodes[index].Name)
+			}
+			configured[index] = true
+			electricityPrice := costConfig.ElectricityPricePerKWh
+			if nodeCosts.ElectricityPricePerKWh != nil {
+				electricityPrice = *nodeCosts.ElectricityPricePerKWh
+			}
+			costTargets = append(costTargets, collectors.CostTarget{Client: clients[index], Costs: nodeCosts, ElectricityPrice: electricityPrice})
+		}
+		prometheus.MustRegister(collectors.NewCostCollector(costTargets, powerCollector))
+	}
+
+	var bandwidthDBTargets []collectors.BandwidthDBTarget
+	if getBoolEnv("BANDWIDTH_DB_ENABLED") {
+		for i, node := range nodes {
+			if node.DBPath == "" {
+				continue
+			}
+			db, err := nodedb.OpenBandwidthDB(node.DBPath)
+			if err != nil {
+				log.Fatalf("Error opening bandwidth.db of node %s: %v\n", node.URL, err)
+			}
+			bandwidthDBTargets = append(bandwidthDBTargets, collectors.BandwidthDBTarget{NodeID: clients[i].NodeID, DB: db})
+		}
+	}
+	if len(bandwidthDBTargets) > 0 {
+		prometheus.MustRegister(collectors.NewBandwidthDBCollector(bandwidthDBTargets))
+	}
+
+	var pieceExpirationTargets []collectors.PieceExpirationTarget
+	if getBoolEnv("PIECE_EXPIRATION_DB_ENABLED") {
+		for i, node := range nodes {
+			if node.DBPath == "" {
+				continue
+			}
+			db, err := nodedb.OpenPieceExpirationDB(node.DBPath)
+			if err != nil {
+				log.Fatalf("Error opening piece_expiration.db of node %s: %v\n", node.URL, err)
+			}
+			pieceExpirationTargets = append(pieceExpirationTargets, collectors.PieceExpirationTarget{NodeID: clients[i].NodeID, DB: db})
+		}
+	}
+	if len(pieceExpirationTargets) > 0 {
+		prometheus.MustRegister(collectors.NewPieceExpirationCollector(pieceExpirationTargets, getDurationEnv("PIECE_EXPIRATION_REFRESH_INTERVAL", time.Minute*15)))
+	}
+
+	usedSpaceDBs := make(map[string]*nodedb.UsedSpaceDB)
+	if getBoolEnv("USED_SPACE_DB_ENABLED") {
+		for i, node := range nodes {
+			if node.DBPath == "" {
+				continue
+			}
+			db, err := nodedb.OpenUsedSpaceDB(node.DBPath)
+			if err != nil {
+				log.Fatalf("Error opening used_space_per_prefix.db of node %s: %v\n", node.URL, err)
+			}
+			usedSpaceDBs[clients[i].NodeID] = db
+		}
+	}
+
+	logCollector := collectors.NewLogCollector()
+	auditFailureCollector := collectors.NewAuditFailureCollector(getIntEnv("AUDIT_FAILURE_HISTORY", 100))
+	filewalkerCollector := collectors.NewFilewalkerCollector(usedSpaceDBs)
+	gcCollector := collectors.NewGCCollector()
+	logHandlers := []logs.Handler{logCollector, auditFailureCollector, filewalkerCollector, gcCollector}
+	logSources := getLogSources(nodes)
+	if len(logSources) > 0 {
+		prometheus.MustRegister(logCollector)
+		prometheus.MustRegister(auditFailureCollector)
+		prometheus.MustRegister(gcCollector)
+		http.Handle("/audit-failures", auditFailureCollector)
+	}
+	if len(logSources) > 0 || len(usedSpaceDBs) > 0 {
+		prometheus.MustRegister(filewalkerCollector)
+	}
+	for i, source := range logSources {
+		if source != nil {
+			go logs.Tail(source, clients[i].NodeID, logHandlers)
+		}
+	}
+
 	port := 8000 // Default port
     if value, exists := os.LookupEnv("EXPORTER_PORT"); exists {
 		if intValue, err := strconv.Atoi(value); err != nil {
@@ -40,23 +360,45 @@ func Run() {
     }
 
 	http.Handle("/metrics", promhttp.Handler())
+	http.Handle("/notifications", notificationCollector)
 	log.Printf("Starting Storj Node Exporter on :%d", port)
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", port), nil))
 }
 
-func getNodeURLs() []string {
-	var urls []string
-	for i := 1; ; i++ {
-		NodeURL := os.Getenv(fmt.Sprintf("STORJ_NODE_%d_URL", i))
-		if NodeURL == "" {
-			break
-		}
-		url, err := url.Parse(NodeURL)
-		if err != nil {
-			log.Printf("Error parsing URL for node %d, %s: %v", i, NodeURL, err)
+// getLogSources returns the log source of every node, nil for nodes without one.
+// The result is empty if no node has a log source configured.
+func getLogSources(nodes []nodeConfig) []logs.Source {
+	var dockerClient *api.DockerClient
+	sources := make([]logs.Source, len(nodes))
+	configured := false
+	for i, node := range nodes {
+		switch {
+		case node.LogFile != "":
+			sources[i] = logs.NewFileSource(node.LogFile)
+		case node.LogContainer != "":
+			if dockerClient == nil {
+				dockerClient = newDockerClient()
+			}
+			sources[i] = logs.NewDockerSource(dockerClient, node.LogContainer)
+		default:
 			continue
 		}
-		urls = append(urls, url.String())
+		configured = true
+	}
+	if !configured {
+		return nil
 	}
-	return urls
-}
\ No newline at end of file
+	return sources
+}
+
+func newDockerClient() *api.DockerClient {
+	dockerHost := api.DefaultDockerHost
+	if value, exists := os.LookupEnv("DOCKER_HOST"); exists {
+		dockerHost = value
+	}
+	client, err := api.NewDockerClient(dockerHost)
+	if err != nil {
+		log.Fatalf("Invalid DOCKER_HOST: %v\n", err)
+	}
+	return client
+}
@@ -0,0 +1,133 @@
+package collectors
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Error classes of failed audit and repair downloads, matched in order against the lowercased error message.
+var pieceErrorClasses = []struct {
+	class    string
+	patterns []string
+}{
+	{"file_not_found", []string{"file does not exist", "no such file or directory"}},
+	{"database_locked", []string{"database is locked"}},
+	{"timeout", []string{"timeout", "deadline exceeded"}},
+	{"canceled", []string{"context canceled"}},
+	{"corrupted", []string{"hash", "checksum", "corrupt"}},
+	{"io_error", []string{"input/output error", "i/o error"}},
+	{"connection", []string{"connection reset", "broken pipe", "closed network connection", "connection refused"}},
+}
+
+type pieceFailureKey struct {
+	nodeID      string
+	satelliteID string
+	action      string
+	errorClass  string
+}
+
+// AuditFailureCollector keeps the most recent failed GET_AUDIT and GET_REPAIR downloads
+// from the node logs and counts them by error class.
+type AuditFailureCollector struct {
+	mu       sync.Mutex
+	counts   map[pieceFailureKey]float64
+	failures []models.PieceFailure
+	next     int
+	metrics  map[string]*prometheus.Desc
+}
+
+func NewAuditFailureCollector(historySize int) *AuditFailureCollector {
+	return &AuditFailureCollector{
+		counts:   make(map[pieceFailureKey]float64),
+		failures: make([]models.PieceFailure, 0, historySize),
+		metrics: map[string]*prometheus.Desc{
+			"failures": prometheus.NewDesc(
+				"storj_log_piece_failures_total",
+				"Failed audit and repair downloads seen in the node logs by error class",
+				[]string{"node_id", "satellite_id", "action", "error_class"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *AuditFailureCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *AuditFailureCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.metrics["failures"], prometheus.CounterValue, count, key.nodeID, key.satelliteID, key.action, key.errorClass)
+	}
+}
+
+func (c *AuditFailureCollector) HandleLog(nodeID string, entry *models.LogEntry) {
+	action := entry.Field("Action")
+	if !strings.HasPrefix(entry.Message, "download failed") || (action != "GET_AUDIT" && action != "GET_REPAIR") {
+		return
+	}
+
+	failure := models.PieceFailure{
+		NodeID:      nodeID,
+		Time:        entry.Time,
+		SatelliteID: entry.Field("Satellite ID"),
+		PieceID:     entry.Field("Piece ID"),
+		Action:      action,
+		Error:       entry.Field("error"),
+	}
+	failure.ErrorClass = pieceErrorClass(failure.Error)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[pieceFailureKey{nodeID: nodeID, satelliteID: failure.SatelliteID, action: action, errorClass: failure.ErrorClass}]++
+
+	if cap(c.failures) == 0 {
+		return
+	}
+	if len(c.failures) < cap(c.failures) {
+		c.failures = append(c.failures, failure)
+	} else {
+		c.failures[c.next] = failure
+	}
+	c.next = (c.next + 1) % cap(c.failures)
+}
+
+// ServeHTTP exposes the most recent failures as JSON, newest first.
+func (c *AuditFailureCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	failures := make([]models.PieceFailure, 0, len(c.failures))
+	for i := 1; i <= len(c.failures); i++ {
+		failures = append(failures, c.failures[(c.next-i+len(c.failures))%len(c.failures)])
+	}
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(failures); err != nil {
+		log.Printf("Error encoding piece failures: %v", err)
+	}
+}
+
+func pieceErrorClass(message string) string {
+	message = strings.ToLower(message)
+	for _, errorClass := range pieceErrorClasses {
+		for _, pattern := range errorClass.patterns {
+			if strings.Contains(message, pattern) {
+				return errorClass.class
+			}
+		}
+	}
+	return "other"
+}
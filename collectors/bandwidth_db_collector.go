@@ -0,0 +1,57 @@
+package collectors
+
+import (
+	"log"
+	"time"
+
+	"github.com/akash329d/storj_exporter/nodedb"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BandwidthDBTarget pairs a node with its bandwidth database.
+type BandwidthDBTarget struct {
+	NodeID string
+	DB     *nodedb.BandwidthDB
+}
+
+type BandwidthDBCollector struct {
+	targets []BandwidthDBTarget
+	metrics map[string]*prometheus.Desc
+}
+
+func NewBandwidthDBCollector(targets []BandwidthDBTarget) *BandwidthDBCollector {
+	return &BandwidthDBCollector{
+		targets: targets,
+		metrics: map[string]*prometheus.Desc{
+			"bandwidth": prometheus.NewDesc(
+				"storj_bandwidth_db_bytes",
+				"Bandwidth used per satellite and piece action in the current hour, day and month (UTC), read from bandwidth.db",
+				[]string{"node_id", "satellite_id", "action", "period"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *BandwidthDBCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *BandwidthDBCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		usage, err := target.DB.Usage(time.Now())
+		if err != nil {
+			log.Printf("Error collecting bandwidth.db metrics for node [%s]: %v", target.NodeID, err)
+			continue
+		}
+
+		for _, data := range usage {
+			ch <- prometheus.MustNewConstMetric(c.metrics["bandwidth"], prometheus.GaugeValue, float64(data.Hour), target.NodeID, data.SatelliteID, data.Action, "hour")
+			ch <- prometheus.MustNewConstMetric(c.metrics["bandwidth"], prometheus.GaugeValue, float64(data.Day), target.NodeID, data.SatelliteID, data.Action, "day")
+			ch <- prometheus.MustNewConstMetric(c.metrics["bandwidth"], prometheus.GaugeValue, float64(data.Month), target.NodeID, data.SatelliteID, data.Action, "month")
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package collectors
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+	"github.com/akash329d/storj_exporter/nodeconfig"
+	"github.com/akash329d/storj_exporter/storage"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConfigTarget pairs a node with the path of its config.yaml and, if known, its storage directory
+// and private API, which reports the external address the node advertises.
+type ConfigTarget struct {
+	Client      *api.ApiClient
+	ConfigPath  string
+	StoragePath string
+	Private     *api.PrivateClient
+}
+
+// allocatedTolerance is the relative difference between the allocation in config.yaml and the one the node reports
+// that is still considered equal, the node may round the allocation it reports.
+const allocatedTolerance = 0.01
+
+// ConfigCollector exports the storagenode config.yaml and compares it against what the running node reports,
+// catching misconfiguration such as a wrong wallet or more allocated space than the disk holds.
+// The node data is the last one observed by the NodeCollector.
+type ConfigCollector struct {
+	targets []ConfigTarget
+	mu      sync.Mutex
+	nodes   map[string]models.NodeData
+	metrics map[string]*prometheus.Desc
+}
+
+func NewConfigCollector(targets []ConfigTarget) *ConfigCollector {
+	return &ConfigCollector{
+		targets: targets,
+		nodes:   make(map[string]models.NodeData),
+		metrics: map[string]*prometheus.Desc{
+			"info": prometheus.NewDesc(
+				"storj_config_info",
+				"Settings from the node config.yaml",
+				[]string{"node_id", "external_address", "wallet", "debug_address"},
+				nil,
+			),
+			"allocatedDiskSpace": prometheus.NewDesc(
+				"storj_config_allocated_disk_space_bytes",
+				"Disk space allocated to the node in config.yaml",
+				[]string{"node_id"},
+				nil,
+			),
+			"operatorEmailSet": prometheus.NewDesc(
+				"storj_config_operator_email_set",
+				"Indicates if an operator email is configured in config.yaml",
+				[]string{"node_id"},
+				nil,
+			),
+			"mismatch": prometheus.NewDesc(
+				"storj_config_mismatch",
+				"Indicates if config.yaml disagrees with the running node or the filesystem",
+				[]string{"node_id", "check"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *ConfigCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *ConfigCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		config, err := nodeconfig.Load(target.ConfigPath)
+		if err != nil {
+			log.Printf("Error reading config of node [%s]: %v", target.Client.NodeID, err)
+			continue
+		}
+
+		c.collectConfigMetrics(ch, target.Client.NodeID, &config)
+
+		c.mu.Lock()
+		node, ok := c.nodes[target.Client.NodeID]
+		c.mu.Unlock()
+		if ok {
+			c.collectMismatchMetrics(ch, &target, &config, &node)
+		}
+	}
+}
+
+// ObserveNode keeps the node data for the config checks, a node that could not be reached is not checked.
+func (c *ConfigCollector) ObserveNode(nodeID string, node *models.NodeData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if node == nil {
+		delete(c.nodes, nodeID)
+		return
+	}
+	c.nodes[nodeID] = *node
+}
+
+func (c *ConfigCollector) collectConfigMetrics(ch chan<- prometheus.Metric, nodeID string, config *models.NodeConfig) {
+	ch <- prometheus.MustNewConstMetric(
+		c.metrics["info"],
+		prometheus.GaugeValue,
+		1,
+		nodeID,
+		config.ExternalAddress,
+		config.Wallet,
+		config.DebugAddress,
+	)
+
+	ch <- prometheus.MustNewConstMetric(c.metrics["allocatedDiskSpace"], prometheus.GaugeValue, float64(config.AllocatedDiskSpace), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["operatorEmailSet"], prometheus.GaugeValue, boolToFloat64(config.Email != ""), nodeID)
+}
+
+func (c *ConfigCollector) collectMismatchMetrics(ch chan<- prometheus.Metric, target *ConfigTarget, config *models.NodeConfig, node *models.NodeData) {
+	nodeID := target.Client.NodeID
+
+	// Docker nodes pass the wallet, email and allocation as flags, leaving config.yaml empty.
+	// Only settings present in config.yaml are compared.
+	checks := make(map[string]bool)
+	if config.Wallet != "" {
+		checks["wallet"] = !strings.EqualFold(config.Wallet, node.Wallet)
+	}
+	if len(config.WalletFeatures) > 0 {
+		checks["wallet_features"] = !sameWalletFeatures(config.WalletFeatures, node.WalletFeatures)
+	}
+	if config.AllocatedDiskSpace > 0 {
+		// The node reports its allocation as the available disk space, it differs until the node is restarted after a config change.
+		checks["allocated_disk_space"] = math.Abs(float64(config.AllocatedDiskSpace-node.DiskSpace.Available)) > allocatedTolerance*float64(config.AllocatedDiskSpace)
+	}
+
+	// Without contact.external-address the node advertises the address it detected, there is nothing to compare.
+	if config.ExternalAddress != "" && target.Private != nil {
+		dashboard, err := target.Private.Dashboard()
+		if err != nil {
+			log.Printf("Error collecting advertised address for config checks of node [%s]: %v", nodeID, err)
+		} else if dashboard.ExternalAddress != "" {
+			checks["external_address"] = !strings.EqualFold(config.ExternalAddress, dashboard.ExternalAddress)
+		}
+	}
+
+	if target.StoragePath != "" && config.AllocatedDiskSpace > 0 {
+		stats, err := storage.Statfs(target.StoragePath)
+		if err != nil {
+			log.Printf("Error collecting filesystem stats for %s: %v", target.StoragePath, err)
+		} else {
+			checks["allocated_exceeds_filesystem"] = uint64(config.AllocatedDiskSpace) > stats.Size
+		}
+	}
+
+	for check, mismatch := range checks {
+		ch <- prometheus.MustNewConstMetric(c.metrics["mismatch"], prometheus.GaugeValue, boolToFloat64(mismatch), nodeID, check)
+	}
+}
+
+func sameWalletFeatures(a, b []string) bool {
+	normalize := func(features []string) string {
+		normalized := make([]string, 0, len(features))
+		for _, feature := range features {
+			normalized = append(normalized, strings.ToLower(strings.TrimSpace(feature)))
+		}
+		sort.Strings(normalized)
+		return strings.Join(normalized, ",")
+	}
+	return normalize(a) == normalize(b)
+}
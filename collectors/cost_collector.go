@@ -0,0 +1,152 @@
+package collectors
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// hoursPerMonth is the length of an average month, used to turn power draw into monthly electricity cost.
+const hoursPerMonth = 730
+
+// CostTarget pairs a node with its configured costs and the electricity price that applies to it.
+type CostTarget struct {
+	Client           *api.ApiClient
+	Costs            models.NodeCosts
+	ElectricityPrice float64
+}
+
+// CostCollector turns the expected payout of each node into profit using its configured costs. Electricity
+// is taken from a power meter when one measures the node, otherwise from the configured power draw.
+type CostCollector struct {
+	targets  []CostTarget
+	power    *PowerCollector
+	mu       sync.Mutex
+	joinedAt map[string]time.Time
+	metrics  map[string]*prometheus.Desc
+}
+
+// NewCostCollector creates a cost collector, power may be nil if no power meters are configured.
+func NewCostCollector(targets []CostTarget, power *PowerCollector) *CostCollector {
+	return &CostCollector{
+		targets:  targets,
+		power:    power,
+		joinedAt: make(map[string]time.Time),
+		metrics: map[string]*prometheus.Desc{
+			"cost": prometheus.NewDesc(
+				"storj_cost_monthly_dollars",
+				"Monthly cost of the node by type, disk purchase amortized, electricity and internet share",
+				[]string{"node_id", "type"},
+				nil,
+			),
+			"profit": prometheus.NewDesc(
+				"storj_profit_monthly_dollars",
+				"Expected payout of the current month minus the monthly cost of the node",
+				[]string{"node_id"},
+				nil,
+			),
+			"profitPerTB": prometheus.NewDesc(
+				"storj_profit_per_tb_stored_dollars",
+				"Monthly profit of the node per TB of data stored",
+				[]string{"node_id"},
+				nil,
+			),
+			"breakEven": prometheus.NewDesc(
+				"storj_break_even_projected_timestamp",
+				"Projected date when the disk is paid back, assuming the node earned the current month's expected profit before disk cost every month since it joined. Young nodes earn less, so the projection is optimistic",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *CostCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *CostCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		nodeID := target.Client.NodeID
+
+		payout, err := target.Client.Payout()
+		if err != nil {
+			log.Printf("Error collecting node payout data for costs: %v", err)
+			continue
+		}
+		node, err := target.Client.Node()
+		if err != nil {
+			log.Printf("Error collecting node data for costs: %v", err)
+			continue
+		}
+
+		watts := target.Costs.PowerWatts
+		if c.power != nil {
+			if measured, ok := c.power.NodeWatts(nodeID); ok {
+				watts = measured
+			}
+		}
+
+		costs := map[string]float64{
+			"electricity": watts / 1000 * hoursPerMonth * target.ElectricityPrice,
+			"internet":    target.Costs.InternetMonthly,
+			"disk":        0,
+		}
+		if target.Costs.DiskAmortizationMonths > 0 {
+			costs["disk"] = target.Costs.DiskPrice / target.Costs.DiskAmortizationMonths
+		}
+		var total float64
+		for costType, cost := range costs {
+			total += cost
+			ch <- prometheus.MustNewConstMetric(c.metrics["cost"], prometheus.GaugeValue, cost, nodeID, costType)
+		}
+
+		expected := payout.CurrentMonthExpectations / 100
+		profit := expected - total
+		ch <- prometheus.MustNewConstMetric(c.metrics["profit"], prometheus.GaugeValue, profit, nodeID)
+		if node.DiskSpace.Used > 0 {
+			ch <- prometheus.MustNewConstMetric(c.metrics["profitPerTB"], prometheus.GaugeValue, profit/(float64(node.DiskSpace.Used)/1e12), nodeID)
+		}
+
+		// The disk is paid for once, so it is paid back by the profit before its amortization. Actual earnings
+		// since joining are not available, so the current month is projected back to the join date.
+		operatingProfit := expected - costs["electricity"] - costs["internet"]
+		joinedAt := c.nodeJoinedAt(target.Client)
+		if target.Costs.DiskPrice > 0 && operatingProfit > 0 && !joinedAt.IsZero() {
+			months := target.Costs.DiskPrice / operatingProfit
+			breakEven := joinedAt.Add(time.Duration(months * hoursPerMonth * float64(time.Hour)))
+			ch <- prometheus.MustNewConstMetric(c.metrics["breakEven"], prometheus.GaugeValue, float64(breakEven.Unix()), nodeID)
+		}
+	}
+}
+
+// nodeJoinedAt returns when the node joined its first satellite. It never changes, so it is only requested once per node.
+func (c *CostCollector) nodeJoinedAt(client *api.ApiClient) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if joinedAt, ok := c.joinedAt[client.NodeID]; ok {
+		return joinedAt
+	}
+
+	var joinedAt time.Time
+	for _, satellite := range client.Satellites {
+		data, err := client.Satellite(satellite.ID)
+		if err != nil {
+			log.Printf("Error collecting satellite data for costs: %v", err)
+			return time.Time{}
+		}
+		if !data.NodeJoinedAt.IsZero() && (joinedAt.IsZero() || data.NodeJoinedAt.Before(joinedAt)) {
+			joinedAt = data.NodeJoinedAt
+		}
+	}
+	c.joinedAt[client.NodeID] = joinedAt
+	return joinedAt
+}
@@ -0,0 +1,124 @@
+package collectors
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultDebugAllowlist keeps the piece transfer, garbage collection and filewalker statistics
+// out of the several thousand series a storagenode debug endpoint exposes.
+const DefaultDebugAllowlist = `(?i)upload|download|audit|repair|retain|bloom|gc|garbage|filewalker|walk|trash|used_space|piece_?delet|expir`
+
+// DebugTarget pairs a node with the client for its debug address.
+type DebugTarget struct {
+	NodeID   string
+	NodeName string
+	Client   *api.DebugClient
+}
+
+// DebugCollector relabels the monkit statistics of each node with node_id and node_name.
+// The exported series depend on the storagenode version, so the collector is unchecked
+// and does not describe any metrics up front.
+type DebugCollector struct {
+	targets   []DebugTarget
+	allowlist *regexp.Regexp
+}
+
+func NewDebugCollector(targets []DebugTarget, allowlist *regexp.Regexp) *DebugCollector {
+	return &DebugCollector{
+		targets:   targets,
+		allowlist: allowlist,
+	}
+}
+
+func (c *DebugCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *DebugCollector) Collect(ch chan<- prometheus.Metric) {
+	// Shared across targets, different storagenode versions may export the same name with other labels.
+	families := make(map[string]debugFamily)
+	seen := make(map[string]bool)
+	for _, target := range c.targets {
+		metrics, err := target.Client.Metrics()
+		if err != nil {
+			log.Printf("Error collecting debug metrics from %s: %v", target.Client.BaseURL, err)
+			continue
+		}
+
+		for _, metric := range metrics {
+			if !c.allowed(&metric) {
+				continue
+			}
+			c.collectDebugMetric(ch, target.NodeID, target.NodeName, &metric, families, seen)
+		}
+	}
+}
+
+// debugFamily is the type and label names of the first series exported for a metric name.
+// Prometheus rejects a scrape in which series of the same name disagree on either.
+type debugFamily struct {
+	valueType  prometheus.ValueType
+	labelNames string
+}
+
+// allowed matches the allowlist against the metric name and, for generic monkit series such as
+// function timings, against the name of the instrumented function.
+func (c *DebugCollector) allowed(metric *models.DebugMetric) bool {
+	return c.allowlist.MatchString(metric.Name) || c.allowlist.MatchString(metric.Labels["name"])
+}
+
+func (c *DebugCollector) collectDebugMetric(ch chan<- prometheus.Metric, nodeID, nodeName string, metric *models.DebugMetric, families map[string]debugFamily, seen map[string]bool) {
+	labelNames := make([]string, 0, len(metric.Labels)+2)
+	for name := range metric.Labels {
+		if name != "node_id" && name != "node_name" && !strings.HasPrefix(name, "__") {
+			labelNames = append(labelNames, name)
+		}
+	}
+	sort.Strings(labelNames)
+
+	labelValues := make([]string, 0, len(labelNames)+2)
+	for _, name := range labelNames {
+		labelValues = append(labelValues, metric.Labels[name])
+	}
+	labelNames = append(labelNames, "node_id", "node_name")
+	labelValues = append(labelValues, nodeID, nodeName)
+
+	valueType := prometheus.UntypedValue
+	switch metric.Type {
+	case models.DebugCounter:
+		valueType = prometheus.CounterValue
+	case models.DebugGauge:
+		valueType = prometheus.GaugeValue
+	}
+
+	// Inconsistent or duplicate series would fail the whole scrape, so only the first one is kept.
+	name := "storj_debug_" + metric.Name
+	family := debugFamily{valueType: valueType, labelNames: strings.Join(labelNames, "\xff")}
+	if known, ok := families[name]; !ok {
+		families[name] = family
+	} else if known != family {
+		log.Printf("Dropping debug metric %s of node [%s]: type or labels %v differ from an earlier series", metric.Name, nodeID, labelNames)
+		return
+	}
+
+	key := name + "\xff" + strings.Join(labelValues, "\xff")
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+
+	desc := prometheus.NewDesc(name, "Storagenode debug metric "+metric.Name, labelNames, nil)
+	m, err := prometheus.NewConstMetric(desc, valueType, metric.Value, labelValues...)
+	if err != nil {
+		log.Printf("Error relabeling debug metric %s: %v", metric.Name, err)
+		return
+	}
+	ch <- m
+}
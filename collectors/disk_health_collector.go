@@ -0,0 +1,147 @@
+package collectors
+
+import (
+	"log"
+
+	"github.com/akash329d/storj_exporter/models"
+	"github.com/akash329d/storj_exporter/smart"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DiskHealthTarget maps a node to the disks holding its data, either devices polled with smartctl
+// or files with smartctl JSON output written by a cron job.
+type DiskHealthTarget struct {
+	NodeID  string
+	Devices []string
+	Files   []string
+}
+
+// DiskHealthCollector exports the SMART data of the disks of each node labeled with its node_id.
+type DiskHealthCollector struct {
+	targets []DiskHealthTarget
+	poller  *smart.Poller
+	metrics map[string]*prometheus.Desc
+}
+
+func NewDiskHealthCollector(targets []DiskHealthTarget, poller *smart.Poller) *DiskHealthCollector {
+	return &DiskHealthCollector{
+		targets: targets,
+		poller:  poller,
+		metrics: map[string]*prometheus.Desc{
+			"info": prometheus.NewDesc(
+				"storj_disk_info",
+				"Disk of the node with its model and serial number",
+				[]string{"node_id", "device", "model", "serial"},
+				nil,
+			),
+			"passed": prometheus.NewDesc(
+				"storj_disk_smart_passed",
+				"Indicates if the overall SMART health self-assessment of the disk passed",
+				[]string{"node_id", "device"},
+				nil,
+			),
+			"temperature": prometheus.NewDesc(
+				"storj_disk_temperature_celsius",
+				"Current temperature of the disk",
+				[]string{"node_id", "device"},
+				nil,
+			),
+			"powerOnHours": prometheus.NewDesc(
+				"storj_disk_power_on_hours",
+				"Power on time of the disk in hours",
+				[]string{"node_id", "device"},
+				nil,
+			),
+			"sectors": prometheus.NewDesc(
+				"storj_disk_sectors",
+				"Reallocated, pending and offline uncorrectable sectors of ATA disks",
+				[]string{"node_id", "device", "type"},
+				nil,
+			),
+			"mediaErrors": prometheus.NewDesc(
+				"storj_disk_media_errors",
+				"Unrecovered data integrity errors of NVMe disks",
+				[]string{"node_id", "device"},
+				nil,
+			),
+			"percentageUsed": prometheus.NewDesc(
+				"storj_disk_percentage_used",
+				"Estimated share of the endurance of NVMe disks used in percent",
+				[]string{"node_id", "device"},
+				nil,
+			),
+			"readAt": prometheus.NewDesc(
+				"storj_disk_smart_read_timestamp",
+				"Timestamp when the SMART data of the disk was read",
+				[]string{"node_id", "device"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *DiskHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *DiskHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		// A disk listed both as device and as file, or twice, would export duplicate series and fail the scrape.
+		seen := make(map[string]bool)
+		for _, device := range target.Devices {
+			if health, ok := c.poller.Health(device); ok && !seen[health.Device] {
+				seen[health.Device] = true
+				c.collectDiskHealth(ch, target.NodeID, &health)
+			}
+		}
+
+		for _, file := range target.Files {
+			health, err := smart.ReadFile(file)
+			if err != nil {
+				log.Printf("Error reading SMART data of node [%s]: %v", target.NodeID, err)
+				continue
+			}
+			if seen[health.Device] {
+				log.Printf("Skipping SMART data of node [%s] from %s, device %s is already reported", target.NodeID, file, health.Device)
+				continue
+			}
+			seen[health.Device] = true
+			c.collectDiskHealth(ch, target.NodeID, &health)
+		}
+	}
+}
+
+func (c *DiskHealthCollector) collectDiskHealth(ch chan<- prometheus.Metric, nodeID string, health *models.DiskHealth) {
+	device := health.Device
+	ch <- prometheus.MustNewConstMetric(c.metrics["info"], prometheus.GaugeValue, 1, nodeID, device, health.Model, health.Serial)
+	ch <- prometheus.MustNewConstMetric(c.metrics["passed"], prometheus.GaugeValue, boolToFloat64(health.Passed), nodeID, device)
+	if !health.ReadAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.metrics["readAt"], prometheus.GaugeValue, float64(health.ReadAt.Unix()), nodeID, device)
+	}
+
+	values := map[string]*float64{
+		"temperature":    health.TemperatureCelsius,
+		"powerOnHours":   health.PowerOnHours,
+		"mediaErrors":    health.MediaErrors,
+		"percentageUsed": health.PercentageUsed,
+	}
+	for name, value := range values {
+		if value != nil {
+			ch <- prometheus.MustNewConstMetric(c.metrics[name], prometheus.GaugeValue, *value, nodeID, device)
+		}
+	}
+
+	sectors := map[string]*float64{
+		"reallocated":           health.ReallocatedSectors,
+		"pending":               health.PendingSectors,
+		"offline_uncorrectable": health.OfflineUncorrectable,
+	}
+	for sectorType, value := range sectors {
+		if value != nil {
+			ch <- prometheus.MustNewConstMetric(c.metrics["sectors"], prometheus.GaugeValue, *value, nodeID, device, sectorType)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package collectors
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+	"github.com/akash329d/storj_exporter/nodedb"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Filewalkers as named in the storagenode logs, mapped to the walker label.
+var filewalkers = map[string]string{
+	"used-space-filewalker":    "used_space",
+	"gc-filewalker":            "gc",
+	"trash-cleanup-filewalker": "trash_cleanup",
+	"trash-filewalker":         "trash_cleanup",
+}
+
+type filewalkerKey struct {
+	nodeID      string
+	satelliteID string
+	walker      string
+}
+
+type filewalkerRun struct {
+	running      bool
+	startedAt    time.Time
+	completedAt  time.Time
+	failedAt     time.Time
+	lastDuration time.Duration
+	completed    float64
+	failed       float64
+}
+
+// FilewalkerCollector follows the filewalker runs of each node in its logs and, when available,
+// the progress the used-space filewalker saved to used_space_per_prefix.db.
+type FilewalkerCollector struct {
+	usedSpaceDBs map[string]*nodedb.UsedSpaceDB
+	mu           sync.Mutex
+	runs         map[filewalkerKey]*filewalkerRun
+	metrics      map[string]*prometheus.Desc
+}
+
+func NewFilewalkerCollector(usedSpaceDBs map[string]*nodedb.UsedSpaceDB) *FilewalkerCollector {
+	return &FilewalkerCollector{
+		usedSpaceDBs: usedSpaceDBs,
+		runs:         make(map[filewalkerKey]*filewalkerRun),
+		metrics: map[string]*prometheus.Desc{
+			"running": prometheus.NewDesc(
+				"storj_filewalker_running",
+				"Indicates if the filewalker is currently running for the satellite",
+				[]string{"node_id", "satellite_id", "walker"},
+				nil,
+			),
+			"started": prometheus.NewDesc(
+				"storj_filewalker_started_timestamp",
+				"Timestamp when the last filewalker run for the satellite started",
+				[]string{"node_id", "satellite_id", "walker"},
+				nil,
+			),
+			"completed": prometheus.NewDesc(
+				"storj_filewalker_last_completed_timestamp",
+				"Timestamp when the last successful filewalker run for the satellite completed",
+				[]string{"node_id", "satellite_id", "walker"},
+				nil,
+			),
+			"failed": prometheus.NewDesc(
+				"storj_filewalker_last_failed_timestamp",
+				"Timestamp when the last filewalker run for the satellite failed",
+				[]string{"node_id", "satellite_id", "walker"},
+				nil,
+			),
+			"duration": prometheus.NewDesc(
+				"storj_filewalker_last_duration_seconds",
+				"Duration of the last successful filewalker run for the satellite",
+				[]string{"node_id", "satellite_id", "walker"},
+				nil,
+			),
+			"runs": prometheus.NewDesc(
+				"storj_filewalker_runs_total",
+				"Finished filewalker runs seen in the node logs by result",
+				[]string{"node_id", "satellite_id", "walker", "result"},
+				nil,
+			),
+			"progress": prometheus.NewDesc(
+				"storj_filewalker_progress_ratio",
+				"Share of piece prefixes the running used-space filewalker has saved to used_space_per_prefix.db",
+				[]string{"node_id", "satellite_id", "walker"},
+				nil,
+			),
+			"cachePrefixes": prometheus.NewDesc(
+				"storj_used_space_cache_prefixes",
+				"Number of piece prefixes recorded in used_space_per_prefix.db",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"cacheBytes": prometheus.NewDesc(
+				"storj_used_space_cache_bytes",
+				"Used space recorded in used_space_per_prefix.db, total on disk and piece content",
+				[]string{"node_id", "satellite_id", "type"},
+				nil,
+			),
+			"cachePieces": prometheus.NewDesc(
+				"storj_used_space_cache_pieces",
+				"Number of pieces recorded in used_space_per_prefix.db",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"cacheUpdated": prometheus.NewDesc(
+				"storj_used_space_cache_updated_timestamp",
+				"Oldest and newest update of the piece prefixes recorded in used_space_per_prefix.db",
+				[]string{"node_id", "satellite_id", "type"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *FilewalkerCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *FilewalkerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, run := range c.runs {
+		ch <- prometheus.MustNewConstMetric(c.metrics["running"], prometheus.GaugeValue, boolToFloat64(run.running), key.nodeID, key.satelliteID, key.walker)
+		ch <- prometheus.MustNewConstMetric(c.metrics["runs"], prometheus.CounterValue, run.completed, key.nodeID, key.satelliteID, key.walker, "completed")
+		ch <- prometheus.MustNewConstMetric(c.metrics["runs"], prometheus.CounterValue, run.failed, key.nodeID, key.satelliteID, key.walker, "failed")
+
+		timestamps := map[string]time.Time{"started": run.startedAt, "completed": run.completedAt, "failed": run.failedAt}
+		for name, timestamp := range timestamps {
+			if !timestamp.IsZero() {
+				ch <- prometheus.MustNewConstMetric(c.metrics[name], prometheus.GaugeValue, float64(timestamp.Unix()), key.nodeID, key.satelliteID, key.walker)
+			}
+		}
+		if run.lastDuration > 0 {
+			ch <- prometheus.MustNewConstMetric(c.metrics["duration"], prometheus.GaugeValue, run.lastDuration.Seconds(), key.nodeID, key.satelliteID, key.walker)
+		}
+	}
+
+	for nodeID, db := range c.usedSpaceDBs {
+		prefixes, err := db.Prefixes()
+		if err != nil {
+			log.Printf("Error collecting used_space_per_prefix.db metrics for node [%s]: %v", nodeID, err)
+			continue
+		}
+		c.collectUsedSpaceCache(ch, nodeID, prefixes)
+	}
+}
+
+func (c *FilewalkerCollector) collectUsedSpaceCache(ch chan<- prometheus.Metric, nodeID string, prefixes []models.UsedSpacePrefix) {
+	type satelliteCache struct {
+		prefixes, totalBytes, contentSize, pieces, updatedSinceStart int64
+		oldest, newest                                               time.Time
+	}
+
+	satellites := make(map[string]*satelliteCache)
+	for _, prefix := range prefixes {
+		cache, ok := satellites[prefix.SatelliteID]
+		if !ok {
+			cache = &satelliteCache{oldest: prefix.UpdatedAt, newest: prefix.UpdatedAt}
+			satellites[prefix.SatelliteID] = cache
+		}
+		cache.prefixes++
+		cache.totalBytes += prefix.TotalBytes
+		cache.contentSize += prefix.TotalContentSize
+		cache.pieces += prefix.Pieces
+		if prefix.UpdatedAt.Before(cache.oldest) {
+			cache.oldest = prefix.UpdatedAt
+		}
+		if prefix.UpdatedAt.After(cache.newest) {
+			cache.newest = prefix.UpdatedAt
+		}
+
+		// Prefixes saved after the running walk started are the ones it has already visited.
+		run := c.runs[filewalkerKey{nodeID: nodeID, satelliteID: prefix.SatelliteID, walker: "used_space"}]
+		if run != nil && run.running && !prefix.UpdatedAt.Before(run.startedAt) {
+			cache.updatedSinceStart++
+		}
+	}
+
+	for satelliteID, cache := range satellites {
+		ch <- prometheus.MustNewConstMetric(c.metrics["cachePrefixes"], prometheus.GaugeValue, float64(cache.prefixes), nodeID, satelliteID)
+		ch <- prometheus.MustNewConstMetric(c.metrics["cacheBytes"], prometheus.GaugeValue, float64(cache.totalBytes), nodeID, satelliteID, "total")
+		ch <- prometheus.MustNewConstMetric(c.metrics["cacheBytes"], prometheus.GaugeValue, float64(cache.contentSize), nodeID, satelliteID, "content")
+		ch <- prometheus.MustNewConstMetric(c.metrics["cachePieces"], prometheus.GaugeValue, float64(cache.pieces), nodeID, satelliteID)
+		ch <- prometheus.MustNewConstMetric(c.metrics["cacheUpdated"], prometheus.GaugeValue, float64(cache.oldest.Unix()), nodeID, satelliteID, "oldest")
+		ch <- prometheus.MustNewConstMetric(c.metrics["cacheUpdated"], prometheus.GaugeValue, float64(cache.newest.Unix()), nodeID, satelliteID, "newest")
+
+		run := c.runs[filewalkerKey{nodeID: nodeID, satelliteID: satelliteID, walker: "used_space"}]
+		if run != nil && run.running {
+			ch <- prometheus.MustNewConstMetric(c.metrics["progress"], prometheus.GaugeValue, float64(cache.updatedSinceStart)/nodedb.UsedSpacePrefixes, nodeID, satelliteID, "used_space")
+		}
+	}
+}
+
+// HandleLog tracks filewalker runs. Lazy filewalkers run as a subprocess and log both from the
+// subprocess ("used-space-filewalker started") and from the node ("subprocess started").
+func (c *FilewalkerCollector) HandleLog(nodeID string, entry *models.LogEntry) {
+	walker, event := filewalkerEvent(entry)
+	if walker == "" || event == "" {
+		return
+	}
+
+	satelliteID := entry.Field("satelliteID")
+	if satelliteID == "" {
+		satelliteID = entry.Field("Satellite ID")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := filewalkerKey{nodeID: nodeID, satelliteID: satelliteID, walker: walker}
+	run, ok := c.runs[key]
+	if !ok {
+		run = &filewalkerRun{}
+		c.runs[key] = run
+	}
+
+	switch event {
+	case "started":
+		if !run.running {
+			run.running = true
+			run.startedAt = entry.Time
+		}
+	case "completed":
+		if run.running {
+			run.running = false
+			run.completed++
+			if !run.startedAt.IsZero() {
+				run.lastDuration = entry.Time.Sub(run.startedAt)
+			}
+		}
+		run.completedAt = entry.Time
+	case "failed":
+		if run.running {
+			run.running = false
+			run.failed++
+		}
+		run.failedAt = entry.Time
+	}
+}
+
+// filewalkerEvent returns the walker and whether the entry marks the start, completion or failure of a run.
+func filewalkerEvent(entry *models.LogEntry) (walker string, event string) {
+	name := ""
+	if strings.HasPrefix(entry.Logger, "lazyfilewalker.") {
+		name = strings.SplitN(strings.TrimPrefix(entry.Logger, "lazyfilewalker."), ".", 2)[0]
+	} else if index := strings.Index(entry.Message, "filewalker"); index >= 0 {
+		name = entry.Message[:index+len("filewalker")]
+	}
+	walker, ok := filewalkers[name]
+	if !ok {
+		return "", ""
+	}
+
+	message := entry.Message
+	switch {
+	case message == "subprocess started" || message == name+" started":
+		return walker, "started"
+	case message == "subprocess finished successfully" || message == name+" completed":
+		return walker, "completed"
+	case strings.EqualFold(entry.Level, "ERROR") || strings.Contains(message, "failed") || strings.HasPrefix(message, "subprocess exited"):
+		return walker, "failed"
+	}
+	return walker, ""
+}
@@ -0,0 +1,198 @@
+package collectors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const gcDuplicateWindow = time.Minute
+
+type gcKey struct {
+	nodeID      string
+	satelliteID string
+}
+
+type gcRun struct {
+	filterReceivedAt time.Time
+	filterCreatedAt  time.Time
+	filterSize       float64
+	completedAt      time.Time
+	duration         time.Duration
+	piecesCount      float64
+	piecesTrashed    float64
+	piecesTrashedSum float64
+	runs             float64
+}
+
+// GCCollector follows garbage collection in the node logs. Satellites periodically send a bloom filter
+// of the pieces the node should keep, retain then moves every older piece not in the filter to the trash.
+type GCCollector struct {
+	mu      sync.Mutex
+	runs    map[gcKey]*gcRun
+	metrics map[string]*prometheus.Desc
+}
+
+func NewGCCollector() *GCCollector {
+	return &GCCollector{
+		runs: make(map[gcKey]*gcRun),
+		metrics: map[string]*prometheus.Desc{
+			"filterReceived": prometheus.NewDesc(
+				"storj_gc_bloom_filter_received_timestamp",
+				"Timestamp when the node started processing the last bloom filter from the satellite",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"filterCreated": prometheus.NewDesc(
+				"storj_gc_bloom_filter_created_before_timestamp",
+				"Pieces created before this timestamp are checked against the last bloom filter from the satellite",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"filterSize": prometheus.NewDesc(
+				"storj_gc_bloom_filter_size_bytes",
+				"Size of the last bloom filter from the satellite",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"completed": prometheus.NewDesc(
+				"storj_gc_last_completed_timestamp",
+				"Timestamp when the last garbage collection for the satellite completed",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"duration": prometheus.NewDesc(
+				"storj_gc_last_duration_seconds",
+				"Duration of the last garbage collection for the satellite",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"piecesCount": prometheus.NewDesc(
+				"storj_gc_last_pieces_checked",
+				"Number of pieces checked against the bloom filter in the last garbage collection",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"piecesTrashed": prometheus.NewDesc(
+				"storj_gc_last_pieces_trashed",
+				"Number of pieces moved to the trash in the last garbage collection",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"piecesTrashedTotal": prometheus.NewDesc(
+				"storj_gc_pieces_trashed_total",
+				"Number of pieces moved to the trash by garbage collection",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"runs": prometheus.NewDesc(
+				"storj_gc_runs_total",
+				"Number of completed garbage collections",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *GCCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *GCCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, run := range c.runs {
+		timestamps := map[string]time.Time{"filterReceived": run.filterReceivedAt, "filterCreated": run.filterCreatedAt, "completed": run.completedAt}
+		for name, timestamp := range timestamps {
+			if !timestamp.IsZero() {
+				ch <- prometheus.MustNewConstMetric(c.metrics[name], prometheus.GaugeValue, float64(timestamp.Unix()), key.nodeID, key.satelliteID)
+			}
+		}
+
+		if !run.filterReceivedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.metrics["filterSize"], prometheus.GaugeValue, run.filterSize, key.nodeID, key.satelliteID)
+		}
+
+		if !run.completedAt.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.metrics["duration"], prometheus.GaugeValue, run.duration.Seconds(), key.nodeID, key.satelliteID)
+			ch <- prometheus.MustNewConstMetric(c.metrics["piecesCount"], prometheus.GaugeValue, run.piecesCount, key.nodeID, key.satelliteID)
+			ch <- prometheus.MustNewConstMetric(c.metrics["piecesTrashed"], prometheus.GaugeValue, run.piecesTrashed, key.nodeID, key.satelliteID)
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.metrics["piecesTrashedTotal"], prometheus.CounterValue, run.piecesTrashedSum, key.nodeID, key.satelliteID)
+		ch <- prometheus.MustNewConstMetric(c.metrics["runs"], prometheus.CounterValue, run.runs, key.nodeID, key.satelliteID)
+	}
+}
+
+// HandleLog tracks the retain messages of the node:
+//
+//	retain  Prepared to run a Retain request.  {"Created Before": ..., "Filter Size": ..., "Satellite ID": ...}
+//	retain  Moved pieces to trash during retain  {"Deleted pieces": ..., "Pieces count": ..., "Duration": ..., "Satellite ID": ...}
+//
+// With the lazy filewalker enabled the result is logged by the gc-filewalker subprocess instead.
+func (c *GCCollector) HandleLog(nodeID string, entry *models.LogEntry) {
+	var event string
+	switch {
+	case entry.Logger == "retain" && entry.Message == "Prepared to run a Retain request.":
+		event = "received"
+	case entry.Logger == "retain" && entry.Message == "Moved pieces to trash during retain":
+		event = "completed"
+	case entry.Message == "gc-filewalker completed":
+		event = "completed"
+	default:
+		return
+	}
+
+	satelliteID := entry.Field("Satellite ID")
+	if satelliteID == "" {
+		satelliteID = entry.Field("satelliteID")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := gcKey{nodeID: nodeID, satelliteID: satelliteID}
+	run, ok := c.runs[key]
+	if !ok {
+		run = &gcRun{}
+		c.runs[key] = run
+	}
+
+	if event == "received" {
+		run.filterReceivedAt = entry.Time
+		run.filterSize = entry.NumberField("Filter Size")
+		if createdBefore, err := time.Parse(time.RFC3339Nano, entry.Field("Created Before")); err == nil {
+			run.filterCreatedAt = createdBefore
+		}
+		return
+	}
+
+	// The lazy gc-filewalker and retain can both report the same run shortly after each other.
+	sameRun := !run.completedAt.IsZero() && entry.Time.Sub(run.completedAt) < gcDuplicateWindow
+	run.completedAt = entry.Time
+
+	if duration, err := time.ParseDuration(entry.Field("Duration")); err == nil {
+		run.duration = duration
+	} else if !run.filterReceivedAt.IsZero() && entry.Time.After(run.filterReceivedAt) {
+		run.duration = entry.Time.Sub(run.filterReceivedAt)
+	}
+
+	if _, ok := entry.Fields["Deleted pieces"]; ok {
+		run.piecesTrashed = entry.NumberField("Deleted pieces")
+		run.piecesCount = entry.NumberField("Pieces count")
+	} else {
+		run.piecesTrashed = entry.NumberField("piecesTrashed")
+		run.piecesCount = entry.NumberField("piecesCount")
+	}
+	if !sameRun {
+		run.runs++
+		run.piecesTrashedSum += run.piecesTrashed
+	}
+}
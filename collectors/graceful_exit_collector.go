@@ -0,0 +1,81 @@
+package collectors
+
+import (
+	"log"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type GracefulExitCollector struct {
+	targets []PrivateTarget
+	metrics map[string]*prometheus.Desc
+}
+
+func NewGracefulExitCollector(targets []PrivateTarget) *GracefulExitCollector {
+	return &GracefulExitCollector{
+		targets: targets,
+		metrics: map[string]*prometheus.Desc{
+			"initiated": prometheus.NewDesc(
+				"storj_graceful_exit_initiated",
+				"Indicates that a graceful exit from the satellite has been initiated",
+				[]string{"node_id", "satellite_id", "satellite_url"},
+				nil,
+			),
+			"percentComplete": prometheus.NewDesc(
+				"storj_graceful_exit_percent_complete",
+				"Progress of the graceful exit from the satellite in percent",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"completed": prometheus.NewDesc(
+				"storj_graceful_exit_completed_timestamp",
+				"Timestamp when the graceful exit from the satellite completed successfully",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"failed": prometheus.NewDesc(
+				"storj_graceful_exit_failed_timestamp",
+				"Timestamp when the graceful exit from the satellite failed",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *GracefulExitCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *GracefulExitCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		progress, err := target.Client.ExitProgress()
+		if err != nil {
+			log.Printf("Error collecting graceful exit progress from %s: %v", target.Client.Address, err)
+			continue
+		}
+
+		for _, exit := range progress {
+			c.collectExitProgress(ch, target.NodeID, &exit)
+		}
+	}
+}
+
+func (c *GracefulExitCollector) collectExitProgress(ch chan<- prometheus.Metric, nodeID string, exit *models.ExitProgress) {
+	ch <- prometheus.MustNewConstMetric(c.metrics["initiated"], prometheus.GaugeValue, 1, nodeID, exit.SatelliteID, exit.DomainName)
+	ch <- prometheus.MustNewConstMetric(c.metrics["percentComplete"], prometheus.GaugeValue, exit.PercentComplete, nodeID, exit.SatelliteID)
+
+	if exit.FinishedAt.IsZero() {
+		return
+	}
+
+	finished := "failed"
+	if exit.Successful {
+		finished = "completed"
+	}
+	ch <- prometheus.MustNewConstMetric(c.metrics[finished], prometheus.GaugeValue, float64(exit.FinishedAt.Unix()), nodeID, exit.SatelliteID)
+}
@@ -0,0 +1,96 @@
+package collectors
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/akash329d/storj_exporter/identity"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// IdentityTarget pairs a node with its identity directory.
+type IdentityTarget struct {
+	NodeID string
+	Dir    string
+}
+
+// IdentityCollector inspects the identity certificates of each node. The files are read on every
+// scrape, so swapping in the identity of another node shows up before the node is restarted.
+type IdentityCollector struct {
+	targets []IdentityTarget
+	metrics map[string]*prometheus.Desc
+}
+
+func NewIdentityCollector(targets []IdentityTarget) *IdentityCollector {
+	return &IdentityCollector{
+		targets: targets,
+		metrics: map[string]*prometheus.Desc{
+			"info": prometheus.NewDesc(
+				"storj_identity_info",
+				"Node ID derived from the identity certificates and the identity version",
+				[]string{"node_id", "identity_node_id", "version"},
+				nil,
+			),
+			"difficulty": prometheus.NewDesc(
+				"storj_identity_difficulty",
+				"Difficulty of the node identity, the number of trailing zero bits of the node ID",
+				[]string{"node_id"},
+				nil,
+			),
+			"expiry": prometheus.NewDesc(
+				"storj_identity_cert_expiry_timestamp",
+				"Timestamp when the identity or CA certificate expires",
+				[]string{"node_id", "cert"},
+				nil,
+			),
+			"chainValid": prometheus.NewDesc(
+				"storj_identity_chain_valid",
+				"Indicates if identity.cert is signed by the CA in ca.cert",
+				[]string{"node_id"},
+				nil,
+			),
+			"mismatch": prometheus.NewDesc(
+				"storj_identity_mismatch",
+				"Indicates if the identity directory belongs to a different node than the one answering the API",
+				[]string{"node_id"},
+				nil,
+			),
+			"up": prometheus.NewDesc(
+				"storj_identity_up",
+				"Indicates if the identity certificates could be read and parsed",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *IdentityCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *IdentityCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		identity, err := identity.Load(target.Dir)
+		if err != nil {
+			log.Printf("Error reading identity of node [%s] from %s: %v", target.NodeID, target.Dir, err)
+			ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 0, target.NodeID)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 1, target.NodeID)
+		c.collectIdentityMetrics(ch, target.NodeID, &identity)
+	}
+}
+
+func (c *IdentityCollector) collectIdentityMetrics(ch chan<- prometheus.Metric, nodeID string, identity *models.Identity) {
+	ch <- prometheus.MustNewConstMetric(c.metrics["info"], prometheus.GaugeValue, 1, nodeID, identity.NodeID, strconv.Itoa(identity.Version))
+	ch <- prometheus.MustNewConstMetric(c.metrics["difficulty"], prometheus.GaugeValue, float64(identity.Difficulty), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["expiry"], prometheus.GaugeValue, float64(identity.CertExpiresAt.Unix()), nodeID, "identity")
+	ch <- prometheus.MustNewConstMetric(c.metrics["expiry"], prometheus.GaugeValue, float64(identity.CAExpiresAt.Unix()), nodeID, "ca")
+	ch <- prometheus.MustNewConstMetric(c.metrics["chainValid"], prometheus.GaugeValue, boolToFloat64(identity.ChainValid), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["mismatch"], prometheus.GaugeValue, boolToFloat64(identity.NodeID != nodeID), nodeID)
+}
@@ -0,0 +1,130 @@
+package collectors
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Log messages of the piecestore endpoint, the outcome is derived from their prefix.
+var transferOutcomes = []struct {
+	prefix  string
+	outcome string
+}{
+	{"uploaded", "success"},
+	{"upload canceled", "canceled"},
+	{"upload failed", "failed"},
+	{"downloaded", "success"},
+	{"download canceled", "canceled"},
+	{"download failed", "failed"},
+}
+
+type transferKey struct {
+	nodeID      string
+	satelliteID string
+	action      string
+	outcome     string
+}
+
+type levelKey struct {
+	nodeID string
+	level  string
+}
+
+type transferCount struct {
+	count float64
+	bytes float64
+}
+
+// LogCollector counts piece transfers by action, outcome and satellite from the storagenode logs.
+type LogCollector struct {
+	mu        sync.Mutex
+	transfers map[transferKey]*transferCount
+	levels    map[levelKey]float64
+	metrics   map[string]*prometheus.Desc
+}
+
+func NewLogCollector() *LogCollector {
+	return &LogCollector{
+		transfers: make(map[transferKey]*transferCount),
+		levels:    make(map[levelKey]float64),
+		metrics: map[string]*prometheus.Desc{
+			"transfers": prometheus.NewDesc(
+				"storj_log_transfers_total",
+				"Piece transfers seen in the node logs by action and outcome",
+				[]string{"node_id", "satellite_id", "action", "outcome"},
+				nil,
+			),
+			"transferBytes": prometheus.NewDesc(
+				"storj_log_transfer_bytes_total",
+				"Size of the piece transfers seen in the node logs by action and outcome",
+				[]string{"node_id", "satellite_id", "action", "outcome"},
+				nil,
+			),
+			"entries": prometheus.NewDesc(
+				"storj_log_entries_total",
+				"Log entries seen in the node logs by level",
+				[]string{"node_id", "level"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *LogCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *LogCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, transfer := range c.transfers {
+		ch <- prometheus.MustNewConstMetric(c.metrics["transfers"], prometheus.CounterValue, transfer.count, key.nodeID, key.satelliteID, key.action, key.outcome)
+		ch <- prometheus.MustNewConstMetric(c.metrics["transferBytes"], prometheus.CounterValue, transfer.bytes, key.nodeID, key.satelliteID, key.action, key.outcome)
+	}
+
+	for key, count := range c.levels {
+		ch <- prometheus.MustNewConstMetric(c.metrics["entries"], prometheus.CounterValue, count, key.nodeID, key.level)
+	}
+}
+
+func (c *LogCollector) HandleLog(nodeID string, entry *models.LogEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.levels[levelKey{nodeID: nodeID, level: strings.ToLower(entry.Level)}]++
+
+	outcome := transferOutcome(entry.Message)
+	if outcome == "" {
+		return
+	}
+
+	key := transferKey{
+		nodeID:      nodeID,
+		satelliteID: entry.Field("Satellite ID"),
+		action:      entry.Field("Action"),
+		outcome:     outcome,
+	}
+	transfer, ok := c.transfers[key]
+	if !ok {
+		transfer = &transferCount{}
+		c.transfers[key] = transfer
+	}
+	transfer.count++
+	transfer.bytes += entry.NumberField("Size")
+}
+
+func transferOutcome(message string) string {
+	for _, transfer := range transferOutcomes {
+		if strings.HasPrefix(message, transfer.prefix) {
+			return transfer.outcome
+		}
+	}
+	return ""
+}
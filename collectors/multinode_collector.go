@@ -0,0 +1,134 @@
+package collectors
+
+import (
+	"log"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MultinodeCollector exports the nodes managed by a multinode dashboard, so nodes whose own dashboard
+// is not reachable from the exporter can still be monitored.
+type MultinodeCollector struct {
+	client  *api.MultinodeClient
+	metrics map[string]*prometheus.Desc
+}
+
+func NewMultinodeCollector(client *api.MultinodeClient) *MultinodeCollector {
+	return &MultinodeCollector{
+		client: client,
+		metrics: map[string]*prometheus.Desc{
+			"up": prometheus.NewDesc(
+				"storj_multinode_up",
+				"Indicates if the multinode dashboard answered the last request for its nodes",
+				nil,
+				nil,
+			),
+			"nodeInfo": prometheus.NewDesc(
+				"storj_multinode_node_info",
+				"Node managed by the multinode dashboard with its name, version and status",
+				[]string{"node_id", "node_name", "version", "status"},
+				nil,
+			),
+			"nodeOnline": prometheus.NewDesc(
+				"storj_multinode_node_online",
+				"Indicates if the multinode dashboard could reach the node",
+				[]string{"node_id"},
+				nil,
+			),
+			"lastContact": prometheus.NewDesc(
+				"storj_multinode_last_contact_timestamp",
+				"Timestamp of the last contact of the node with a satellite",
+				[]string{"node_id"},
+				nil,
+			),
+			"diskSpace": prometheus.NewDesc(
+				"storj_multinode_disk_space_bytes",
+				"Disk space of the node by type",
+				[]string{"node_id", "type"},
+				nil,
+			),
+			"bandwidth": prometheus.NewDesc(
+				"storj_multinode_bandwidth_used_bytes",
+				"Bandwidth used by the node in the current month",
+				[]string{"node_id"},
+				nil,
+			),
+			"payout": prometheus.NewDesc(
+				"storj_multinode_payout_cents",
+				"Earned, held and paid amounts of the node in cents",
+				[]string{"node_id", "type"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *MultinodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *MultinodeCollector) Collect(ch chan<- prometheus.Metric) {
+	nodes, err := c.client.Nodes()
+	if err != nil {
+		log.Printf("Error collecting nodes from the multinode dashboard %s: %v", c.client.BaseURL, err)
+		ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 0)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 1)
+
+	for _, node := range nodes {
+		c.collectNodeMetrics(ch, &node)
+		if node.Status != "online" {
+			continue
+		}
+
+		// The node list only has used and left space, the disk space of the node itself has the full breakdown.
+		diskSpace, err := c.client.DiskSpace(node.ID)
+		if err != nil {
+			log.Printf("Error collecting multinode disk space: %v", err)
+			ch <- prometheus.MustNewConstMetric(c.metrics["diskSpace"], prometheus.GaugeValue, float64(node.DiskSpaceUsed), node.ID, "used")
+			ch <- prometheus.MustNewConstMetric(c.metrics["diskSpace"], prometheus.GaugeValue, float64(node.DiskSpaceLeft), node.ID, "available")
+			continue
+		}
+		c.collectDiskSpaceMetrics(ch, node.ID, &diskSpace)
+	}
+
+	summary, err := c.client.PayoutSummary()
+	if err != nil {
+		log.Printf("Error collecting multinode payout summary: %v", err)
+		return
+	}
+	for _, payout := range summary.NodeSummary {
+		ch <- prometheus.MustNewConstMetric(c.metrics["payout"], prometheus.GaugeValue, float64(payout.Held), payout.NodeID, "held")
+		ch <- prometheus.MustNewConstMetric(c.metrics["payout"], prometheus.GaugeValue, float64(payout.Paid), payout.NodeID, "paid")
+	}
+}
+
+func (c *MultinodeCollector) collectNodeMetrics(ch chan<- prometheus.Metric, node *models.MultinodeNode) {
+	ch <- prometheus.MustNewConstMetric(c.metrics["nodeInfo"], prometheus.GaugeValue, 1, node.ID, node.Name, node.Version, node.Status)
+	ch <- prometheus.MustNewConstMetric(c.metrics["nodeOnline"], prometheus.GaugeValue, boolToFloat64(node.Status == "online"), node.ID)
+	if !node.LastContact.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.metrics["lastContact"], prometheus.GaugeValue, float64(node.LastContact.Unix()), node.ID)
+	}
+	ch <- prometheus.MustNewConstMetric(c.metrics["payout"], prometheus.GaugeValue, float64(node.TotalEarned), node.ID, "earned")
+	ch <- prometheus.MustNewConstMetric(c.metrics["bandwidth"], prometheus.GaugeValue, float64(node.BandwidthUsed), node.ID)
+}
+
+func (c *MultinodeCollector) collectDiskSpaceMetrics(ch chan<- prometheus.Metric, nodeID string, diskSpace *models.MultinodeDiskSpace) {
+	values := map[string]int64{
+		"allocated": diskSpace.Allocated,
+		"used":      diskSpace.UsedPieces,
+		"trash":     diskSpace.UsedTrash,
+		"free":      diskSpace.Free,
+		"available": diskSpace.Available,
+		"overused":  diskSpace.Overused,
+	}
+	for diskSpaceType, value := range values {
+		ch <- prometheus.MustNewConstMetric(c.metrics["diskSpace"], prometheus.GaugeValue, float64(value), nodeID, diskSpaceType)
+	}
+}
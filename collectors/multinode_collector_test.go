@@ -0,0 +1,85 @@
+package collectors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/akash329d/storj_exporter/api"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMultinodeCollector(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v0/nodes/infos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": "1node", "name": "first", "version": "v1.104.5", "lastContact": "2024-07-01T12:00:00Z", "diskSpaceUsed": 100, "diskSpaceLeft": 900, "bandwidthUsed": 50, "totalEarned": 1234, "status": "online"},
+			{"id": "2node", "name": "second", "version": "v1.103.2", "lastContact": "0001-01-01T00:00:00Z", "status": "offline"}
+		]`)
+	})
+	mux.HandleFunc("/api/v0/payouts/summaries", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"nodeSummary": [{"nodeId": "1node", "nodeName": "first", "held": 100, "paid": 1000}]}`)
+	})
+	mux.HandleFunc("/api/v0/storage/disk-space/1node", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"allocated": 1000, "usedPieces": 80, "usedTrash": 20, "free": 5000, "available": 900, "overused": 0}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	expected := `
+# HELP storj_multinode_up Indicates if the multinode dashboard answered the last request for its nodes
+# TYPE storj_multinode_up gauge
+storj_multinode_up 1
+# HELP storj_multinode_node_info Node managed by the multinode dashboard with its name, version and status
+# TYPE storj_multinode_node_info gauge
+storj_multinode_node_info{node_id="1node",node_name="first",status="online",version="v1.104.5"} 1
+storj_multinode_node_info{node_id="2node",node_name="second",status="offline",version="v1.103.2"} 1
+# HELP storj_multinode_node_online Indicates if the multinode dashboard could reach the node
+# TYPE storj_multinode_node_online gauge
+storj_multinode_node_online{node_id="1node"} 1
+storj_multinode_node_online{node_id="2node"} 0
+# HELP storj_multinode_last_contact_timestamp Timestamp of the last contact of the node with a satellite
+# TYPE storj_multinode_last_contact_timestamp gauge
+storj_multinode_last_contact_timestamp{node_id="1node"} 1.7198352e+09
+# HELP storj_multinode_disk_space_bytes Disk space of the node by type
+# TYPE storj_multinode_disk_space_bytes gauge
+storj_multinode_disk_space_bytes{node_id="1node",type="allocated"} 1000
+storj_multinode_disk_space_bytes{node_id="1node",type="available"} 900
+storj_multinode_disk_space_bytes{node_id="1node",type="free"} 5000
+storj_multinode_disk_space_bytes{node_id="1node",type="overused"} 0
+storj_multinode_disk_space_bytes{node_id="1node",type="trash"} 20
+storj_multinode_disk_space_bytes{node_id="1node",type="used"} 80
+# HELP storj_multinode_bandwidth_used_bytes Bandwidth used by the node in the current month
+# TYPE storj_multinode_bandwidth_used_bytes gauge
+storj_multinode_bandwidth_used_bytes{node_id="1node"} 50
+storj_multinode_bandwidth_used_bytes{node_id="2node"} 0
+# HELP storj_multinode_payout_cents Earned, held and paid amounts of the node in cents
+# TYPE storj_multinode_payout_cents gauge
+storj_multinode_payout_cents{node_id="1node",type="earned"} 1234
+storj_multinode_payout_cents{node_id="1node",type="held"} 100
+storj_multinode_payout_cents{node_id="1node",type="paid"} 1000
+storj_multinode_payout_cents{node_id="2node",type="earned"} 0
+`
+	collector := NewMultinodeCollector(api.NewMultinodeClient(server.URL))
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMultinodeCollectorDown(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	expected := `
+# HELP storj_multinode_up Indicates if the multinode dashboard answered the last request for its nodes
+# TYPE storj_multinode_up gauge
+storj_multinode_up 0
+`
+	collector := NewMultinodeCollector(api.NewMultinodeClient(server.URL))
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,6 +2,7 @@ package collectors
 
 import (
 	"log"
+	"strings"
 	"time"
 
 	"github.com/akash329d/storj_exporter/api"
@@ -10,9 +11,16 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// NodeObserver is told the result of every node data request of the NodeCollector,
+// so other collectors can follow the nodes without requesting the data again. node is nil if the request failed.
+type NodeObserver interface {
+	ObserveNode(nodeID string, node *models.NodeData)
+}
+
 type NodeCollector struct {
-	clients []*api.ApiClient
-	metrics map[string]*prometheus.Desc
+	clients   []*api.ApiClient
+	observers []NodeObserver
+	metrics   map[string]*prometheus.Desc
 }
 
 func NewNodeCollector(clients []*api.ApiClient) *NodeCollector {
@@ -25,6 +33,18 @@ func NewNodeCollector(clients []*api.ApiClient) *NodeCollector {
 				[]string{"node_id", "wallet", "version", "configured_port"},
 				nil,
 			),
+			"walletInfo": prometheus.NewDesc(
+				"storj_node_wallet_info",
+				"Wallet the node is paid to, normalized to lowercase for grouping nodes by wallet",
+				[]string{"node_id", "wallet", "wallet_features"},
+				nil,
+			),
+			"walletPayoutMethod": prometheus.NewDesc(
+				"storj_node_wallet_payout_method",
+				"Payout method the node opted in to through its wallet features",
+				[]string{"node_id", "wallet", "method"},
+				nil,
+			),
 			"satelliteStorageUsed": prometheus.NewDesc(
 				"storj_satellite_storage_used_bytes",
 				"Storage used per satellite",
@@ -79,6 +99,18 @@ func NewNodeCollector(clients []*api.ApiClient) *NodeCollector {
 				[]string{"node_id", "satellite_id", "satellite_url", "status"},
 				nil,
 			),
+			"satelliteState": prometheus.NewDesc(
+				"storj_satellite_state",
+				"State of the node on the satellite (vetting, vetted, suspended, offline_suspended, disqualified)",
+				[]string{"node_id", "satellite_id", "satellite_url", "state"},
+				nil,
+			),
+			"satelliteStateSince": prometheus.NewDesc(
+				"storj_satellite_state_since_timestamp",
+				"Timestamp when the node entered the state on the satellite",
+				[]string{"node_id", "satellite_id", "state"},
+				nil,
+			),
 		},
 	}
 }
@@ -89,13 +121,24 @@ func (c *NodeCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 }
 
+// Observe registers an observer, it must be called before the collector is registered.
+func (c *NodeCollector) Observe(observer NodeObserver) {
+	c.observers = append(c.observers, observer)
+}
+
 func (c *NodeCollector) Collect(ch chan<- prometheus.Metric) {
 	for _, client := range c.clients {
 		node, err := client.Node()
 		if err != nil {
 			log.Printf("Error collecting node metrics: %v", err)
+			for _, observer := range c.observers {
+				observer.ObserveNode(client.NodeID, nil)
+			}
 			continue
 		}
+		for _, observer := range c.observers {
+			observer.ObserveNode(client.NodeID, &node)
+		}
 
 		c.collectNodeInfo(ch, client.NodeID, &node)
 		c.collectSatelliteMetrics(ch, client.NodeID, &node)
@@ -116,6 +159,36 @@ func (c *NodeCollector) collectNodeInfo(ch chan<- prometheus.Metric, nodeID stri
 		node.Version,
 		node.ConfiguredPort,
 	)
+
+	wallet := strings.ToLower(node.Wallet)
+	ch <- prometheus.MustNewConstMetric(
+		c.metrics["walletInfo"],
+		prometheus.GaugeValue,
+		1,
+		nodeID,
+		wallet,
+		strings.Join(node.WalletFeatures, ","),
+	)
+
+	for _, method := range walletPayoutMethods(node.WalletFeatures) {
+		ch <- prometheus.MustNewConstMetric(c.metrics["walletPayoutMethod"], prometheus.GaugeValue, 1, nodeID, wallet, method)
+	}
+}
+
+// walletPayoutMethods maps the node's wallet features to payout methods.
+// Nodes without any wallet feature are paid with an ERC-20 transfer on Ethereum L1.
+func walletPayoutMethods(features []string) []string {
+	var methods []string
+	for _, feature := range features {
+		feature = strings.ToLower(strings.TrimSpace(feature))
+		if feature != "" {
+			methods = append(methods, feature)
+		}
+	}
+	if len(methods) == 0 {
+		return []string{"erc20"}
+	}
+	return methods
 }
 
 func (c *NodeCollector) collectSatelliteMetrics(ch chan<- prometheus.Metric, nodeID string, node *models.NodeData) {
@@ -130,6 +203,7 @@ func (c *NodeCollector) collectSatelliteMetrics(ch chan<- prometheus.Metric, nod
 		)
 
 		c.collectSatelliteStatus(ch, nodeID, &satellite)
+		c.collectSatelliteState(ch, nodeID, &satellite)
 	}
 }
 
@@ -157,6 +231,42 @@ func (c *NodeCollector) collectSatelliteStatus(ch chan<- prometheus.Metric, node
 	}
 }
 
+func (c *NodeCollector) collectSatelliteState(ch chan<- prometheus.Metric, nodeID string, satellite *models.Satellite) {
+	// Vetting and vetted are mutually exclusive, the remaining states can overlap with either of them.
+	states := map[string]*time.Time{
+		"vetted":            satellite.VettedAt,
+		"suspended":         satellite.Suspended,
+		"offline_suspended": satellite.OfflineSuspended,
+		"disqualified":      satellite.Disqualified,
+	}
+
+	ch <- prometheus.MustNewConstMetric(
+		c.metrics["satelliteState"],
+		prometheus.GaugeValue,
+		boolToFloat64(satellite.VettedAt == nil),
+		nodeID,
+		satellite.ID,
+		satellite.URL,
+		"vetting",
+	)
+
+	for state, since := range states {
+		ch <- prometheus.MustNewConstMetric(
+			c.metrics["satelliteState"],
+			prometheus.GaugeValue,
+			boolToFloat64(since != nil),
+			nodeID,
+			satellite.ID,
+			satellite.URL,
+			state,
+		)
+
+		if since != nil {
+			ch <- prometheus.MustNewConstMetric(c.metrics["satelliteStateSince"], prometheus.GaugeValue, float64(since.Unix()), nodeID, satellite.ID, state)
+		}
+	}
+}
+
 func (c *NodeCollector) collectDiskSpaceMetrics(ch chan<- prometheus.Metric, nodeID string, node *models.NodeData) {
 	diskSpace := node.DiskSpace
 	ch <- prometheus.MustNewConstMetric(c.metrics["diskSpace"], prometheus.GaugeValue, float64(diskSpace.Used), nodeID, "used")
@@ -0,0 +1,123 @@
+package collectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Notification types as defined by the storagenode notifications service.
+var notificationTypes = map[int]string{
+	0: "custom",
+	1: "audit_check_failure",
+	2: "disqualification",
+	3: "suspension",
+	4: "offline",
+}
+
+type NotificationCollector struct {
+	clients []*api.ApiClient
+	metrics map[string]*prometheus.Desc
+}
+
+func NewNotificationCollector(clients []*api.ApiClient) *NotificationCollector {
+	return &NotificationCollector{
+		clients: clients,
+		metrics: map[string]*prometheus.Desc{
+			"unread": prometheus.NewDesc(
+				"storj_notifications_unread",
+				"Number of unread dashboard notifications by type",
+				[]string{"node_id", "type"},
+				nil,
+			),
+			"total": prometheus.NewDesc(
+				"storj_notifications",
+				"Total number of dashboard notifications",
+				[]string{"node_id"},
+				nil,
+			),
+			"latest": prometheus.NewDesc(
+				"storj_notifications_latest_timestamp",
+				"Timestamp of the newest dashboard notification",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *NotificationCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *NotificationCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, client := range c.clients {
+		notifications, err := client.Notifications()
+		if err != nil {
+			log.Printf("Error collecting notifications: %v", err)
+			continue
+		}
+
+		c.collectNotificationMetrics(ch, client.NodeID, notifications)
+	}
+}
+
+func (c *NotificationCollector) collectNotificationMetrics(ch chan<- prometheus.Metric, nodeID string, notifications []models.Notification) {
+	unread := make(map[string]float64, len(notificationTypes))
+	for _, name := range notificationTypes {
+		unread[name] = 0
+	}
+
+	var newest *models.Notification
+	for i, notification := range notifications {
+		if notification.ReadAt == nil {
+			unread[notificationTypeName(notification.Type)]++
+		}
+		if newest == nil || notification.CreatedAt.After(newest.CreatedAt) {
+			newest = &notifications[i]
+		}
+	}
+
+	for name, value := range unread {
+		ch <- prometheus.MustNewConstMetric(c.metrics["unread"], prometheus.GaugeValue, value, nodeID, name)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.metrics["total"], prometheus.GaugeValue, float64(len(notifications)), nodeID)
+
+	if newest != nil {
+		ch <- prometheus.MustNewConstMetric(c.metrics["latest"], prometheus.GaugeValue, float64(newest.CreatedAt.Unix()), nodeID)
+	}
+}
+
+// ServeHTTP exposes the notifications of every node as JSON, keyed by node ID.
+func (c *NotificationCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := make(map[string][]models.Notification, len(c.clients))
+	for _, client := range c.clients {
+		notifications, err := client.Notifications()
+		if err != nil {
+			log.Printf("Error collecting notifications: %v", err)
+			continue
+		}
+		result[client.NodeID] = notifications
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Error encoding notifications: %v", err)
+	}
+}
+
+func notificationTypeName(notificationType int) string {
+	if name, ok := notificationTypes[notificationType]; ok {
+		return name
+	}
+	return fmt.Sprintf("type_%d", notificationType)
+}
@@ -0,0 +1,103 @@
+package collectors
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+	"github.com/akash329d/storj_exporter/nodedb"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PieceExpirationTarget pairs a node with its piece expiration database.
+type PieceExpirationTarget struct {
+	NodeID string
+	DB     *nodedb.PieceExpirationDB
+}
+
+type cachedForecast struct {
+	forecasts []models.PieceExpirationForecast
+	latest    time.Time
+	updated   time.Time
+}
+
+// PieceExpirationCollector forecasts the pieces that will be deleted because their TTL runs out.
+// The expiration table of a busy node holds millions of rows, so the forecast is cached between scrapes.
+type PieceExpirationCollector struct {
+	targets []PieceExpirationTarget
+	refresh time.Duration
+	mu      sync.Mutex
+	cache   map[string]cachedForecast
+	metrics map[string]*prometheus.Desc
+}
+
+func NewPieceExpirationCollector(targets []PieceExpirationTarget, refresh time.Duration) *PieceExpirationCollector {
+	return &PieceExpirationCollector{
+		targets: targets,
+		refresh: refresh,
+		cache:   make(map[string]cachedForecast),
+		metrics: map[string]*prometheus.Desc{
+			"pieces": prometheus.NewDesc(
+				"storj_piece_expiration_pieces",
+				"Number of pieces expiring within the window from now, including expired pieces not deleted yet. Piece sizes are not recorded, so only counts are available",
+				[]string{"node_id", "satellite_id", "within"},
+				nil,
+			),
+			"latest": prometheus.NewDesc(
+				"storj_piece_expiration_db_latest_timestamp",
+				"Latest piece expiration in piece_expiration.db",
+				[]string{"node_id"},
+				nil,
+			),
+			"stale": prometheus.NewDesc(
+				"storj_piece_expiration_db_stale",
+				"Indicates if piece_expiration.db holds no future expirations, as on storagenode versions keeping them in flat files, which are not supported",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *PieceExpirationCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *PieceExpirationCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, target := range c.targets {
+		cached, ok := c.cache[target.NodeID]
+		if !ok || time.Since(cached.updated) >= c.refresh {
+			forecasts, err := target.DB.Forecast(time.Now())
+			if err != nil {
+				log.Printf("Error collecting piece_expiration.db metrics for node [%s]: %v", target.NodeID, err)
+				continue
+			}
+			latest, err := target.DB.LatestExpiration()
+			if err != nil {
+				log.Printf("Error collecting piece_expiration.db metrics for node [%s]: %v", target.NodeID, err)
+				continue
+			}
+			if !latest.After(time.Now()) {
+				log.Printf("piece_expiration.db of node [%s] holds no future expirations, the node likely keeps them in flat files, which are not supported", target.NodeID)
+			}
+			cached = cachedForecast{forecasts: forecasts, latest: latest, updated: time.Now()}
+			c.cache[target.NodeID] = cached
+		}
+
+		if !cached.latest.IsZero() {
+			ch <- prometheus.MustNewConstMetric(c.metrics["latest"], prometheus.GaugeValue, float64(cached.latest.Unix()), target.NodeID)
+		}
+		ch <- prometheus.MustNewConstMetric(c.metrics["stale"], prometheus.GaugeValue, boolToFloat64(!cached.latest.After(time.Now())), target.NodeID)
+
+		for _, forecast := range cached.forecasts {
+			ch <- prometheus.MustNewConstMetric(c.metrics["pieces"], prometheus.GaugeValue, float64(forecast.Pieces), target.NodeID, forecast.SatelliteID, forecast.Window)
+		}
+	}
+}
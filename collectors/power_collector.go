@@ -0,0 +1,174 @@
+package collectors
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/power"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// minAveragingPeriod is how long the energy counter of a meter has to be observed before
+// its average power is preferred over the current power draw.
+const minAveragingPeriod = time.Hour
+
+// PowerTarget is a power meter and the nodes it powers, a single node for a smart plug per disk
+// or all nodes of a host.
+type PowerTarget struct {
+	Meter   *power.Meter
+	Clients []*api.ApiClient
+}
+
+type meterState struct {
+	month       time.Time
+	firstEnergy float64
+	firstAt     time.Time
+	average     float64
+}
+
+// PowerCollector exports the power draw of the nodes and relates it to their earnings. The payout month
+// is compared against the energy used in the same month, estimated from the average power since the
+// exporter started or, until enough is known, the current power draw.
+type PowerCollector struct {
+	targets []PowerTarget
+	mu      sync.Mutex
+	meters  map[string]*meterState
+	metrics map[string]*prometheus.Desc
+}
+
+func NewPowerCollector(targets []PowerTarget) *PowerCollector {
+	return &PowerCollector{
+		targets: targets,
+		meters:  make(map[string]*meterState),
+		metrics: map[string]*prometheus.Desc{
+			"up": prometheus.NewDesc(
+				"storj_power_meter_up",
+				"Indicates if the power meter could be read",
+				[]string{"meter"},
+				nil,
+			),
+			"node": prometheus.NewDesc(
+				"storj_power_meter_node",
+				"Nodes powered by the power meter",
+				[]string{"meter", "node_id"},
+				nil,
+			),
+			"watts": prometheus.NewDesc(
+				"storj_power_watts",
+				"Current power draw measured by the power meter",
+				[]string{"meter"},
+				nil,
+			),
+			"energy": prometheus.NewDesc(
+				"storj_power_energy_kwh_total",
+				"Energy counter of the power meter",
+				[]string{"meter"},
+				nil,
+			),
+			"monthEnergy": prometheus.NewDesc(
+				"storj_power_month_energy_kwh",
+				"Estimated energy used in the current month from the average power draw",
+				[]string{"meter"},
+				nil,
+			),
+			"earningsPerKWh": prometheus.NewDesc(
+				"storj_power_earnings_per_kwh_dollars",
+				"Current month payout of the nodes on the power meter per kWh used in the current month",
+				[]string{"meter"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *PowerCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *PowerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for _, target := range c.targets {
+		name := target.Meter.Name
+		for _, client := range target.Clients {
+			ch <- prometheus.MustNewConstMetric(c.metrics["node"], prometheus.GaugeValue, 1, name, client.NodeID)
+		}
+
+		reading, err := target.Meter.Read()
+		if err != nil {
+			log.Printf("Error reading power meter %s: %v", name, err)
+			ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 0, name)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 1, name)
+		ch <- prometheus.MustNewConstMetric(c.metrics["watts"], prometheus.GaugeValue, reading.Watts, name)
+
+		state, ok := c.meters[name]
+		if !ok || !state.month.Equal(monthStart) {
+			state = &meterState{month: monthStart, firstAt: now}
+			if reading.EnergyKWh != nil {
+				state.firstEnergy = *reading.EnergyKWh
+			}
+			c.meters[name] = state
+		}
+		state.average = reading.Watts
+		if reading.EnergyKWh != nil {
+			ch <- prometheus.MustNewConstMetric(c.metrics["energy"], prometheus.CounterValue, *reading.EnergyKWh, name)
+			if observed := now.Sub(state.firstAt); observed >= minAveragingPeriod && *reading.EnergyKWh >= state.firstEnergy {
+				state.average = (*reading.EnergyKWh - state.firstEnergy) * 1000 / observed.Hours()
+			}
+		}
+
+		monthEnergy := state.average / 1000 * now.Sub(monthStart).Hours()
+		ch <- prometheus.MustNewConstMetric(c.metrics["monthEnergy"], prometheus.GaugeValue, monthEnergy, name)
+		if monthEnergy <= 0 {
+			continue
+		}
+
+		// Without the payout of every node on the meter the earnings would be under-reported, so none are exported.
+		var payoutCents float64
+		complete := true
+		for _, client := range target.Clients {
+			payout, err := client.Payout()
+			if err != nil {
+				log.Printf("Error collecting node payout data for power meter %s: %v", name, err)
+				complete = false
+				break
+			}
+			payoutCents += payout.CurrentMonth.Payout
+		}
+		if complete {
+			ch <- prometheus.MustNewConstMetric(c.metrics["earningsPerKWh"], prometheus.GaugeValue, payoutCents/100/monthEnergy, name)
+		}
+	}
+}
+
+// NodeWatts returns the average power draw attributed to a node, its equal share of every meter it is on.
+func (c *PowerCollector) NodeWatts(nodeID string) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	watts, found := 0.0, false
+	for _, target := range c.targets {
+		state, ok := c.meters[target.Meter.Name]
+		if !ok {
+			continue
+		}
+		for _, client := range target.Clients {
+			if client.NodeID == nodeID {
+				watts += state.average / float64(len(target.Clients))
+				found = true
+			}
+		}
+	}
+	return watts, found
+}
@@ -0,0 +1,108 @@
+package collectors
+
+import (
+	"log"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrivateTarget pairs a node with the client for its private address.
+type PrivateTarget struct {
+	NodeID string
+	Client *api.PrivateClient
+}
+
+type PrivateCollector struct {
+	targets []PrivateTarget
+	metrics map[string]*prometheus.Desc
+}
+
+func NewPrivateCollector(targets []PrivateTarget) *PrivateCollector {
+	return &PrivateCollector{
+		targets: targets,
+		metrics: map[string]*prometheus.Desc{
+			"addressInfo": prometheus.NewDesc(
+				"storj_private_address_info",
+				"Addresses the node is listening on and advertising, as reported by the private API",
+				[]string{"node_id", "internal_address", "external_address", "dashboard_address"},
+				nil,
+			),
+			"nodeConnections": prometheus.NewDesc(
+				"storj_private_node_connections",
+				"Number of nodes in the routing table of the node",
+				[]string{"node_id"},
+				nil,
+			),
+			"uptime": prometheus.NewDesc(
+				"storj_private_uptime_seconds",
+				"Uptime of the storagenode process",
+				[]string{"node_id"},
+				nil,
+			),
+			"lastPinged": prometheus.NewDesc(
+				"storj_private_last_pinged_timestamp",
+				"Timestamp of last ping as reported by the private API",
+				[]string{"node_id"},
+				nil,
+			),
+			"stats": prometheus.NewDesc(
+				"storj_private_stats_bytes",
+				"Space and bandwidth summary as reported by the private API",
+				[]string{"node_id", "type"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *PrivateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *PrivateCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		dashboard, err := target.Client.Dashboard()
+		if err != nil {
+			log.Printf("Error collecting private API metrics from %s: %v", target.Client.Address, err)
+			continue
+		}
+
+		c.collectDashboardMetrics(ch, target.NodeID, &dashboard)
+	}
+}
+
+func (c *PrivateCollector) collectDashboardMetrics(ch chan<- prometheus.Metric, nodeID string, dashboard *models.DashboardData) {
+	ch <- prometheus.MustNewConstMetric(
+		c.metrics["addressInfo"],
+		prometheus.GaugeValue,
+		1,
+		nodeID,
+		dashboard.InternalAddress,
+		dashboard.ExternalAddress,
+		dashboard.DashboardAddress,
+	)
+
+	ch <- prometheus.MustNewConstMetric(c.metrics["nodeConnections"], prometheus.GaugeValue, float64(dashboard.NodeConnections), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["uptime"], prometheus.GaugeValue, dashboard.Uptime.Seconds(), nodeID)
+	if !dashboard.LastPinged.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.metrics["lastPinged"], prometheus.GaugeValue, float64(dashboard.LastPinged.Unix()), nodeID)
+	}
+
+	stats := map[string]int64{
+		"used_space":          dashboard.Stats.UsedSpace,
+		"available_space":     dashboard.Stats.AvailableSpace,
+		"used_ingress":        dashboard.Stats.UsedIngress,
+		"used_egress":         dashboard.Stats.UsedEgress,
+		"used_bandwidth":      dashboard.Stats.UsedBandwidth,
+		"available_bandwidth": dashboard.Stats.AvailableBandwidth,
+	}
+
+	for statType, value := range stats {
+		ch <- prometheus.MustNewConstMetric(c.metrics["stats"], prometheus.GaugeValue, float64(value), nodeID, statType)
+	}
+}
@@ -0,0 +1,147 @@
+package collectors
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+	"github.com/akash329d/storj_exporter/process"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ProcessTarget maps a node to its storagenode process, given as a PID or process name read from /proc,
+// or to its container read through the Docker API.
+type ProcessTarget struct {
+	NodeID    string
+	Process   string
+	Container string
+	Docker    *api.DockerClient
+}
+
+func (t *ProcessTarget) stats() (models.ProcessStats, error) {
+	if t.Container != "" {
+		return t.Docker.ContainerStats(t.Container)
+	}
+
+	// A process name is looked up again on every scrape, the PID changes when the node restarts.
+	pid, err := strconv.Atoi(t.Process)
+	if err != nil {
+		if pid, err = process.Find(t.Process); err != nil {
+			return models.ProcessStats{}, err
+		}
+	}
+	return process.Stats(pid)
+}
+
+// ProcessCollector exports the resource usage of the storagenode process of each node. Restarts are
+// counted by the exporter whenever the start time of the process or container changes.
+type ProcessCollector struct {
+	targets   []ProcessTarget
+	mu        sync.Mutex
+	startedAt map[string]time.Time
+	restarts  map[string]float64
+	metrics   map[string]*prometheus.Desc
+}
+
+func NewProcessCollector(targets []ProcessTarget) *ProcessCollector {
+	return &ProcessCollector{
+		targets:   targets,
+		startedAt: make(map[string]time.Time),
+		restarts:  make(map[string]float64),
+		metrics: map[string]*prometheus.Desc{
+			"up": prometheus.NewDesc(
+				"storj_process_up",
+				"Indicates if the storagenode process or container was found",
+				[]string{"node_id"},
+				nil,
+			),
+			"cpu": prometheus.NewDesc(
+				"storj_process_cpu_seconds_total",
+				"User and system CPU time used by the storagenode process or container",
+				[]string{"node_id"},
+				nil,
+			),
+			"memory": prometheus.NewDesc(
+				"storj_process_memory_bytes",
+				"Resident memory of the storagenode process, or working set of the container",
+				[]string{"node_id"},
+				nil,
+			),
+			"openFDs": prometheus.NewDesc(
+				"storj_process_open_fds",
+				"Number of open file descriptors of the storagenode process",
+				[]string{"node_id"},
+				nil,
+			),
+			"io": prometheus.NewDesc(
+				"storj_process_io_bytes_total",
+				"Bytes read from and written to storage by the storagenode process or container",
+				[]string{"node_id", "direction"},
+				nil,
+			),
+			"started": prometheus.NewDesc(
+				"storj_process_start_time_seconds",
+				"Start time of the storagenode process or container",
+				[]string{"node_id"},
+				nil,
+			),
+			"restarts": prometheus.NewDesc(
+				"storj_process_restarts_total",
+				"Restarts of the storagenode process or container seen by the exporter",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *ProcessCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *ProcessCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, target := range c.targets {
+		stats, err := target.stats()
+		if err != nil {
+			log.Printf("Error collecting process stats of node [%s]: %v", target.NodeID, err)
+			ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 0, target.NodeID)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 1, target.NodeID)
+
+		if !stats.StartedAt.IsZero() {
+			last, ok := c.startedAt[target.NodeID]
+			if ok && !stats.StartedAt.Equal(last) {
+				c.restarts[target.NodeID]++
+			}
+			c.startedAt[target.NodeID] = stats.StartedAt
+		}
+		c.collectProcessStats(ch, target.NodeID, &stats)
+	}
+}
+
+func (c *ProcessCollector) collectProcessStats(ch chan<- prometheus.Metric, nodeID string, stats *models.ProcessStats) {
+	ch <- prometheus.MustNewConstMetric(c.metrics["cpu"], prometheus.CounterValue, stats.CPUSeconds, nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["memory"], prometheus.GaugeValue, float64(stats.MemoryBytes), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["restarts"], prometheus.CounterValue, c.restarts[nodeID], nodeID)
+
+	if stats.OpenFDs >= 0 {
+		ch <- prometheus.MustNewConstMetric(c.metrics["openFDs"], prometheus.GaugeValue, float64(stats.OpenFDs), nodeID)
+	}
+	if stats.HasIO {
+		ch <- prometheus.MustNewConstMetric(c.metrics["io"], prometheus.CounterValue, float64(stats.ReadBytes), nodeID, "read")
+		ch <- prometheus.MustNewConstMetric(c.metrics["io"], prometheus.CounterValue, float64(stats.WriteBytes), nodeID, "write")
+	}
+	if !stats.StartedAt.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.metrics["started"], prometheus.GaugeValue, float64(stats.StartedAt.Unix()), nodeID)
+	}
+}
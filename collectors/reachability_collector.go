@@ -0,0 +1,89 @@
+package collectors
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/probe"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ReachabilityTarget is the external address of a node. Without a configured address it is taken
+// from the private API of the node, which reports the contact.external-address it announces.
+type ReachabilityTarget struct {
+	NodeID  string
+	Address string
+	Private *api.PrivateClient
+}
+
+// ReachabilityCollector dials the external address of each node from the exporter host over TCP and QUIC,
+// an independent check of port forwarding next to what the node itself reports.
+type ReachabilityCollector struct {
+	targets []ReachabilityTarget
+	timeout time.Duration
+	metrics map[string]*prometheus.Desc
+}
+
+func NewReachabilityCollector(targets []ReachabilityTarget, timeout time.Duration) *ReachabilityCollector {
+	return &ReachabilityCollector{
+		targets: targets,
+		timeout: timeout,
+		metrics: map[string]*prometheus.Desc{
+			"success": prometheus.NewDesc(
+				"storj_probe_success",
+				"Indicates if the external address of the node could be reached from the exporter",
+				[]string{"node_id", "address", "protocol"},
+				nil,
+			),
+			"rtt": prometheus.NewDesc(
+				"storj_probe_rtt_seconds",
+				"Round trip time of the last successful probe of the external address",
+				[]string{"node_id", "address", "protocol"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *ReachabilityCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *ReachabilityCollector) Collect(ch chan<- prometheus.Metric) {
+	probes := map[string]func(string, time.Duration) (time.Duration, error){
+		"tcp":  probe.TCP,
+		"quic": probe.QUIC,
+	}
+
+	// Unreachable addresses take the full timeout, probe all nodes and protocols at once.
+	var wg sync.WaitGroup
+	for _, target := range c.targets {
+		address := target.Address
+		if address == "" {
+			dashboard, err := target.Private.Dashboard()
+			if err != nil {
+				log.Printf("Error collecting external address from %s: %v", target.Private.Address, err)
+				continue
+			}
+			address = dashboard.ExternalAddress
+		}
+
+		for protocol, probeAddress := range probes {
+			wg.Add(1)
+			go func(nodeID, address, protocol string, probeAddress func(string, time.Duration) (time.Duration, error)) {
+				defer wg.Done()
+				rtt, err := probeAddress(address, c.timeout)
+				ch <- prometheus.MustNewConstMetric(c.metrics["success"], prometheus.GaugeValue, boolToFloat64(err == nil), nodeID, address, protocol)
+				if err == nil {
+					ch <- prometheus.MustNewConstMetric(c.metrics["rtt"], prometheus.GaugeValue, rtt.Seconds(), nodeID, address, protocol)
+				}
+			}(target.NodeID, address, protocol, probeAddress)
+		}
+	}
+	wg.Wait()
+}
@@ -2,6 +2,7 @@ package collectors
 
 import (
 	"log"
+	"time"
 
 	"github.com/akash329d/storj_exporter/api"
 	"github.com/akash329d/storj_exporter/models"
@@ -9,6 +10,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// gracefulExitMinAgeMonths is the default graceful-exit.node-min-age-in-months of the satellites.
+const gracefulExitMinAgeMonths = 6
+
 type SatelliteCollector struct {
 	clients []*api.ApiClient
 	metrics map[string]*prometheus.Desc
@@ -108,6 +112,18 @@ func NewSatelliteCollector(clients []*api.ApiClient) *SatelliteCollector {
 				[]string{"node_id", "satellite_id"},
 				nil,
 			),
+			"satelliteGracefulExitEligible": prometheus.NewDesc(
+				"storj_satellite_graceful_exit_eligible",
+				"Indicates if the node is old enough to gracefully exit the satellite",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
+			"satelliteGracefulExitEligibleAt": prometheus.NewDesc(
+				"storj_satellite_graceful_exit_eligible_timestamp",
+				"Timestamp from which the node is old enough to gracefully exit the satellite",
+				[]string{"node_id", "satellite_id"},
+				nil,
+			),
 		},
 	}
 }
@@ -248,6 +264,15 @@ func (c *SatelliteCollector) collectSatelliteMetrics(ch chan<- prometheus.Metric
 		nodeID,
 		data.ID,
 	)
+
+	// Satellites only accept a graceful exit from nodes that have been with them for a minimum number of months.
+	// Without a join date eligibility is unknown, so the metrics are left out rather than reported as eligible.
+	if data.NodeJoinedAt.IsZero() {
+		return
+	}
+	eligibleAt := data.NodeJoinedAt.AddDate(0, gracefulExitMinAgeMonths, 0)
+	ch <- prometheus.MustNewConstMetric(c.metrics["satelliteGracefulExitEligible"], prometheus.GaugeValue, boolToFloat64(!time.Now().Before(eligibleAt)), nodeID, data.ID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["satelliteGracefulExitEligibleAt"], prometheus.GaugeValue, float64(eligibleAt.Unix()), nodeID, data.ID)
 }
 
 func (c *SatelliteCollector) collectDailyBandwidth(ch chan<- prometheus.Metric, nodeID, satelliteID string, daily *models.BandwidthDaily) {
@@ -0,0 +1,58 @@
+package collectors
+
+import (
+	"github.com/akash329d/storj_exporter/probe"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SatelliteProbeCollector reports whether the satellites can be reached from the exporter host,
+// to tell a satellite outage apart from a problem with the node's uplink when ingress drops.
+type SatelliteProbeCollector struct {
+	prober  *probe.SatelliteProber
+	metrics map[string]*prometheus.Desc
+}
+
+func NewSatelliteProbeCollector(prober *probe.SatelliteProber) *SatelliteProbeCollector {
+	return &SatelliteProbeCollector{
+		prober: prober,
+		metrics: map[string]*prometheus.Desc{
+			"success": prometheus.NewDesc(
+				"storj_satellite_probe_success",
+				"Indicates if the satellite could be reached from the exporter over TCP or completed a TLS handshake",
+				[]string{"satellite_id", "address", "protocol"},
+				nil,
+			),
+			"latency": prometheus.NewDesc(
+				"storj_satellite_probe_latency_seconds",
+				"Duration of the TCP connect or of the TCP connect and TLS handshake to the satellite",
+				[]string{"satellite_id", "address", "protocol"},
+				nil,
+			),
+			"probed": prometheus.NewDesc(
+				"storj_satellite_probe_timestamp",
+				"Timestamp of the last probe of the satellite",
+				[]string{"satellite_id", "address"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *SatelliteProbeCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *SatelliteProbeCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, result := range c.prober.Results() {
+		ch <- prometheus.MustNewConstMetric(c.metrics["probed"], prometheus.GaugeValue, float64(result.ProbedAt.Unix()), result.SatelliteID, result.Address)
+		for protocol, success := range result.Success {
+			ch <- prometheus.MustNewConstMetric(c.metrics["success"], prometheus.GaugeValue, boolToFloat64(success), result.SatelliteID, result.Address, protocol)
+		}
+		for protocol, latency := range result.Latency {
+			ch <- prometheus.MustNewConstMetric(c.metrics["latency"], prometheus.GaugeValue, latency.Seconds(), result.SatelliteID, result.Address, protocol)
+		}
+	}
+}
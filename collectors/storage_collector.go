@@ -0,0 +1,162 @@
+package collectors
+
+import (
+	"log"
+	"time"
+
+	"github.com/akash329d/storj_exporter/storage"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// trashExpiry is the default storage2.trash-expiry-interval after which the node empties a dated trash directory.
+const trashExpiry = time.Hour * 24 * 7
+
+// StorageTarget pairs a node with the scanner of its storage directory.
+type StorageTarget struct {
+	NodeID  string
+	Scanner *storage.Scanner
+}
+
+// StorageCollector reports the filesystem and directory usage of each node's storage directory as measured
+// by the exporter, independent of what the node itself reports in storj_disk_space_bytes.
+type StorageCollector struct {
+	targets []StorageTarget
+	metrics map[string]*prometheus.Desc
+}
+
+func NewStorageCollector(targets []StorageTarget) *StorageCollector {
+	return &StorageCollector{
+		targets: targets,
+		metrics: map[string]*prometheus.Desc{
+			"filesystemBytes": prometheus.NewDesc(
+				"storj_filesystem_bytes",
+				"Size and free space of the filesystem holding the node storage directory",
+				[]string{"node_id", "path", "type"},
+				nil,
+			),
+			"filesystemInodes": prometheus.NewDesc(
+				"storj_filesystem_inodes",
+				"Total and free inodes of the filesystem holding the node storage directory",
+				[]string{"node_id", "path", "type"},
+				nil,
+			),
+			"directoryBytes": prometheus.NewDesc(
+				"storj_storage_directory_bytes",
+				"Measured size of the directories in the node storage directory",
+				[]string{"node_id", "directory"},
+				nil,
+			),
+			"directoryFiles": prometheus.NewDesc(
+				"storj_storage_directory_files",
+				"Measured number of files in the directories of the node storage directory",
+				[]string{"node_id", "directory"},
+				nil,
+			),
+			"satelliteBytes": prometheus.NewDesc(
+				"storj_storage_satellite_bytes",
+				"Measured size of the blobs and trash of each satellite",
+				[]string{"node_id", "satellite_id", "directory"},
+				nil,
+			),
+			"satellitePieces": prometheus.NewDesc(
+				"storj_storage_satellite_pieces",
+				"Measured number of pieces in the blobs and trash of each satellite",
+				[]string{"node_id", "satellite_id", "directory"},
+				nil,
+			),
+			"trashBytes": prometheus.NewDesc(
+				"storj_storage_trash_bytes",
+				"Measured size of each dated trash directory of a satellite, the date is empty for trash from before dated trash directories",
+				[]string{"node_id", "satellite_id", "date"},
+				nil,
+			),
+			"trashPieces": prometheus.NewDesc(
+				"storj_storage_trash_pieces",
+				"Measured number of pieces in each dated trash directory of a satellite",
+				[]string{"node_id", "satellite_id", "date"},
+				nil,
+			),
+			"trashExpiry": prometheus.NewDesc(
+				"storj_storage_trash_expiry_timestamp",
+				"Timestamp after which the node empties the dated trash directory of a satellite",
+				[]string{"node_id", "satellite_id", "date"},
+				nil,
+			),
+			"scanTimestamp": prometheus.NewDesc(
+				"storj_storage_scan_timestamp",
+				"Timestamp when the last scan of the node storage directory completed",
+				[]string{"node_id"},
+				nil,
+			),
+			"scanDuration": prometheus.NewDesc(
+				"storj_storage_scan_duration_seconds",
+				"Duration of the last scan of the node storage directory",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *StorageCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *StorageCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		c.collectFilesystemMetrics(ch, &target)
+		c.collectDirectoryMetrics(ch, &target)
+	}
+}
+
+func (c *StorageCollector) collectFilesystemMetrics(ch chan<- prometheus.Metric, target *StorageTarget) {
+	path := target.Scanner.Path
+	stats, err := storage.Statfs(path)
+	if err != nil {
+		log.Printf("Error collecting filesystem stats for %s: %v", path, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.metrics["filesystemBytes"], prometheus.GaugeValue, float64(stats.Size), target.NodeID, path, "size")
+	ch <- prometheus.MustNewConstMetric(c.metrics["filesystemBytes"], prometheus.GaugeValue, float64(stats.Free), target.NodeID, path, "free")
+	ch <- prometheus.MustNewConstMetric(c.metrics["filesystemBytes"], prometheus.GaugeValue, float64(stats.Available), target.NodeID, path, "available")
+	ch <- prometheus.MustNewConstMetric(c.metrics["filesystemInodes"], prometheus.GaugeValue, float64(stats.Inodes), target.NodeID, path, "total")
+	ch <- prometheus.MustNewConstMetric(c.metrics["filesystemInodes"], prometheus.GaugeValue, float64(stats.InodesFree), target.NodeID, path, "free")
+}
+
+func (c *StorageCollector) collectDirectoryMetrics(ch chan<- prometheus.Metric, target *StorageTarget) {
+	usage := target.Scanner.Usage()
+	if usage == nil {
+		return
+	}
+
+	for directory, size := range usage.Directories {
+		ch <- prometheus.MustNewConstMetric(c.metrics["directoryBytes"], prometheus.GaugeValue, float64(size.Bytes), target.NodeID, directory)
+		ch <- prometheus.MustNewConstMetric(c.metrics["directoryFiles"], prometheus.GaugeValue, float64(size.Files), target.NodeID, directory)
+	}
+
+	for satellite, size := range usage.Satellites {
+		ch <- prometheus.MustNewConstMetric(c.metrics["satelliteBytes"], prometheus.GaugeValue, float64(size.Bytes), target.NodeID, satellite.SatelliteID, satellite.Directory)
+		ch <- prometheus.MustNewConstMetric(c.metrics["satellitePieces"], prometheus.GaugeValue, float64(size.Files), target.NodeID, satellite.SatelliteID, satellite.Directory)
+	}
+
+	for trash, size := range usage.Trash {
+		var date string
+		if !trash.Date.IsZero() {
+			date = trash.Date.Format("2006-01-02")
+			ch <- prometheus.MustNewConstMetric(c.metrics["trashExpiry"], prometheus.GaugeValue, float64(trash.Date.Add(trashExpiry).Unix()), target.NodeID, trash.SatelliteID, date)
+		}
+		ch <- prometheus.MustNewConstMetric(c.metrics["trashBytes"], prometheus.GaugeValue, float64(size.Bytes), target.NodeID, trash.SatelliteID, date)
+		ch <- prometheus.MustNewConstMetric(c.metrics["trashPieces"], prometheus.GaugeValue, float64(size.Files), target.NodeID, trash.SatelliteID, date)
+	}
+
+	// Until the first scan completed only the per satellite results are available.
+	if usage.ScannedAt.IsZero() {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.metrics["scanTimestamp"], prometheus.GaugeValue, float64(usage.ScannedAt.Unix()), target.NodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["scanDuration"], prometheus.GaugeValue, usage.Duration.Seconds(), target.NodeID)
+}
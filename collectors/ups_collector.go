@@ -0,0 +1,216 @@
+package collectors
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type downtimeKey struct {
+	nodeID string
+	cause  string
+}
+
+type nodeAvailability struct {
+	reachable bool
+	startedAt string
+}
+
+// UPSCollector exports the state of the UPS powering the nodes and counts node downtime, tagging it as
+// power related when the UPS was on battery shortly before. Downtime is a node becoming unreachable or
+// restarting between two observations by the NodeCollector, or a node that restarted shortly before the
+// exporter itself started, as happens when a power cut takes down the whole host.
+type UPSCollector struct {
+	ups     *api.NUTClient
+	window  time.Duration
+	started time.Time
+
+	mu            sync.Mutex
+	status        *models.UPSStatus
+	lastOnBattery time.Time
+	// pending are nodes that restarted around the exporter start before the UPS status was first read.
+	pending  []string
+	nodes    map[string]nodeAvailability
+	downtime map[downtimeKey]float64
+	metrics  map[string]*prometheus.Desc
+}
+
+// NewUPSCollector creates a UPS collector, it learns about the nodes as a NodeObserver.
+func NewUPSCollector(ups *api.NUTClient, window time.Duration) *UPSCollector {
+	return &UPSCollector{
+		ups:      ups,
+		window:   window,
+		started:  time.Now(),
+		nodes:    make(map[string]nodeAvailability),
+		downtime: make(map[downtimeKey]float64),
+		metrics: map[string]*prometheus.Desc{
+			"up": prometheus.NewDesc(
+				"storj_ups_up",
+				"Indicates if the UPS status could be read from the NUT server",
+				[]string{"ups"},
+				nil,
+			),
+			"info": prometheus.NewDesc(
+				"storj_ups_info",
+				"Status flags of the UPS as reported by NUT, e.g. OL for online or OB for on battery",
+				[]string{"ups", "status"},
+				nil,
+			),
+			"onBattery": prometheus.NewDesc(
+				"storj_ups_on_battery",
+				"Indicates if the UPS is running on battery",
+				[]string{"ups"},
+				nil,
+			),
+			"lowBattery": prometheus.NewDesc(
+				"storj_ups_low_battery",
+				"Indicates if the UPS battery is low",
+				[]string{"ups"},
+				nil,
+			),
+			"batteryCharge": prometheus.NewDesc(
+				"storj_ups_battery_charge_percent",
+				"Charge of the UPS battery in percent",
+				[]string{"ups"},
+				nil,
+			),
+			"batteryRuntime": prometheus.NewDesc(
+				"storj_ups_battery_runtime_seconds",
+				"Remaining runtime of the UPS on battery",
+				[]string{"ups"},
+				nil,
+			),
+			"load": prometheus.NewDesc(
+				"storj_ups_load_percent",
+				"Load of the UPS in percent of its capacity",
+				[]string{"ups"},
+				nil,
+			),
+			"lastOnBattery": prometheus.NewDesc(
+				"storj_ups_last_on_battery_timestamp",
+				"Timestamp when the UPS was last seen running on battery",
+				[]string{"ups"},
+				nil,
+			),
+			"downtime": prometheus.NewDesc(
+				"storj_node_downtime_events_total",
+				"Node becoming unreachable or restarting as seen by the exporter, by cause",
+				[]string{"node_id", "cause"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *UPSCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *UPSCollector) Collect(ch chan<- prometheus.Metric) {
+	status, err := c.ups.Status()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		log.Printf("Error collecting UPS status from %s: %v", c.ups.Address, err)
+		ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 0, c.ups.UPS)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.metrics["up"], prometheus.GaugeValue, 1, c.ups.UPS)
+		if status.OnBattery {
+			c.lastOnBattery = time.Now()
+		}
+		c.status = &status
+		for _, nodeID := range c.pending {
+			c.countDowntime(nodeID, c.powerRelated() || c.status.Charging)
+		}
+		c.pending = nil
+		c.collectUPSStatus(ch, &status)
+	}
+	if !c.lastOnBattery.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.metrics["lastOnBattery"], prometheus.GaugeValue, float64(c.lastOnBattery.Unix()), c.ups.UPS)
+	}
+
+	for key, count := range c.downtime {
+		ch <- prometheus.MustNewConstMetric(c.metrics["downtime"], prometheus.CounterValue, count, key.nodeID, key.cause)
+	}
+}
+
+func (c *UPSCollector) collectUPSStatus(ch chan<- prometheus.Metric, status *models.UPSStatus) {
+	ch <- prometheus.MustNewConstMetric(c.metrics["info"], prometheus.GaugeValue, 1, status.Name, status.Status)
+	ch <- prometheus.MustNewConstMetric(c.metrics["onBattery"], prometheus.GaugeValue, boolToFloat64(status.OnBattery), status.Name)
+	ch <- prometheus.MustNewConstMetric(c.metrics["lowBattery"], prometheus.GaugeValue, boolToFloat64(status.LowBattery), status.Name)
+
+	values := map[string]*float64{
+		"batteryCharge":  status.BatteryCharge,
+		"batteryRuntime": status.BatteryRuntime,
+		"load":           status.Load,
+	}
+	for name, value := range values {
+		if value != nil {
+			ch <- prometheus.MustNewConstMetric(c.metrics[name], prometheus.GaugeValue, *value, status.Name)
+		}
+	}
+}
+
+// ObserveNode counts a downtime event when the node became unreachable or restarted since it was last observed.
+func (c *UPSCollector) ObserveNode(nodeID string, node *models.NodeData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := nodeAvailability{}
+	if node != nil {
+		current = nodeAvailability{reachable: true, startedAt: node.StartedAt}
+	}
+
+	previous, seen := c.nodes[nodeID]
+	c.nodes[nodeID] = current
+	if !seen {
+		// Start both causes at zero, so the first event shows up as an increase.
+		c.downtime[downtimeKey{nodeID: nodeID, cause: "power"}] = 0
+		c.downtime[downtimeKey{nodeID: nodeID, cause: "other"}] = 0
+		c.observeFirst(nodeID, &current)
+		return
+	}
+
+	wentDown := previous.reachable && !current.reachable
+	// A restart the exporter did not see as unreachable, e.g. because it happened between two scrapes.
+	restarted := previous.reachable && current.reachable && previous.startedAt != current.startedAt
+	if wentDown || restarted {
+		c.countDowntime(nodeID, c.powerRelated())
+	}
+}
+
+// observeFirst counts a node that started shortly before or after the exporter, the restart of both
+// is not seen between two observations. Without earlier UPS history a recharging battery points to a
+// power cut, so the event is only counted once the UPS status is known.
+func (c *UPSCollector) observeFirst(nodeID string, current *nodeAvailability) {
+	startedAt, err := time.Parse(time.RFC3339, current.startedAt)
+	if !current.reachable || err != nil || startedAt.Before(c.started.Add(-c.window)) {
+		return
+	}
+	if c.status == nil {
+		c.pending = append(c.pending, nodeID)
+		return
+	}
+	c.countDowntime(nodeID, c.powerRelated() || c.status.Charging)
+}
+
+func (c *UPSCollector) powerRelated() bool {
+	return !c.lastOnBattery.IsZero() && time.Since(c.lastOnBattery) <= c.window
+}
+
+func (c *UPSCollector) countDowntime(nodeID string, power bool) {
+	cause := "other"
+	if power {
+		cause = "power"
+	}
+	c.downtime[downtimeKey{nodeID: nodeID, cause: cause}]++
+}
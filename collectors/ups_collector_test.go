@@ -0,0 +1,183 @@
+package collectors
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fakeUPSD is a NUT server reporting a ups.status that tests can change between scrapes.
+type fakeUPSD struct {
+	mu     sync.Mutex
+	status string
+}
+
+func (u *fakeUPSD) setStatus(status string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.status = status
+}
+
+func (u *fakeUPSD) serve(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if !strings.HasPrefix(scanner.Text(), "LIST VAR ") {
+						fmt.Fprint(conn, "OK\n")
+						continue
+					}
+					u.mu.Lock()
+					fmt.Fprintf(conn, "BEGIN LIST VAR ups\nVAR ups ups.status %q\nEND LIST VAR ups\n", u.status)
+					u.mu.Unlock()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func newTestUPSCollector(t *testing.T, status string) (*UPSCollector, *fakeUPSD) {
+	upsd := &fakeUPSD{status: status}
+	return NewUPSCollector(api.NewNUTClient(upsd.serve(t), "ups", "", ""), time.Minute*15), upsd
+}
+
+// downtimeEvents scrapes the collector and returns the downtime counters by node and cause.
+func downtimeEvents(t *testing.T, c *UPSCollector) map[string]float64 {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "storj_node_downtime_events_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			events[labels["node_id"]+"/"+labels["cause"]] = metric.GetCounter().GetValue()
+		}
+	}
+	return events
+}
+
+func startedAgo(d time.Duration) *models.NodeData {
+	return &models.NodeData{StartedAt: time.Now().Add(-d).Format(time.RFC3339)}
+}
+
+func assertEvents(t *testing.T, events map[string]float64, nodeID string, power, other float64) {
+	t.Helper()
+	if events[nodeID+"/power"] != power || events[nodeID+"/other"] != other {
+		t.Errorf("node %s: power=%v other=%v, want power=%v other=%v", nodeID, events[nodeID+"/power"], events[nodeID+"/other"], power, other)
+	}
+}
+
+func TestUPSCollectorWentDown(t *testing.T) {
+	c, _ := newTestUPSCollector(t, "OL")
+	downtimeEvents(t, c)
+
+	c.ObserveNode("node", startedAgo(time.Hour*24))
+	c.ObserveNode("node", nil)
+	// Staying unreachable is the same event.
+	c.ObserveNode("node", nil)
+	assertEvents(t, downtimeEvents(t, c), "node", 0, 1)
+}
+
+func TestUPSCollectorRestartOnBattery(t *testing.T) {
+	c, upsd := newTestUPSCollector(t, "OL")
+	c.ObserveNode("node", startedAgo(time.Hour*24))
+	assertEvents(t, downtimeEvents(t, c), "node", 0, 0)
+
+	upsd.setStatus("OB DISCHRG")
+	downtimeEvents(t, c)
+	upsd.setStatus("OL CHRG")
+	c.ObserveNode("node", startedAgo(time.Minute))
+	assertEvents(t, downtimeEvents(t, c), "node", 1, 0)
+}
+
+func TestUPSCollectorPowerEventWindow(t *testing.T) {
+	c, upsd := newTestUPSCollector(t, "OB")
+	c.ObserveNode("node", startedAgo(time.Hour*24))
+	downtimeEvents(t, c)
+
+	// The UPS was last on battery longer ago than the window.
+	upsd.setStatus("OL")
+	c.mu.Lock()
+	c.lastOnBattery = time.Now().Add(-c.window - time.Minute)
+	c.mu.Unlock()
+
+	c.ObserveNode("node", nil)
+	assertEvents(t, downtimeEvents(t, c), "node", 0, 1)
+}
+
+func TestUPSCollectorRestartAroundExporterStart(t *testing.T) {
+	c, _ := newTestUPSCollector(t, "OL CHRG")
+	downtimeEvents(t, c)
+
+	// A recharging battery without earlier history points to a power cut.
+	c.ObserveNode("restarted", startedAgo(time.Minute*5))
+	// Nodes that were running long before the exporter started are not counted.
+	c.ObserveNode("running", startedAgo(time.Hour*24))
+	c.ObserveNode("unreachable", nil)
+
+	events := downtimeEvents(t, c)
+	assertEvents(t, events, "restarted", 1, 0)
+	assertEvents(t, events, "running", 0, 0)
+	assertEvents(t, events, "unreachable", 0, 0)
+}
+
+func TestUPSCollectorRestartAroundExporterStartOnline(t *testing.T) {
+	c, _ := newTestUPSCollector(t, "OL")
+	downtimeEvents(t, c)
+
+	c.ObserveNode("node", startedAgo(time.Minute*5))
+	assertEvents(t, downtimeEvents(t, c), "node", 0, 1)
+}
+
+func TestUPSCollectorPendingUntilFirstUPSRead(t *testing.T) {
+	c, _ := newTestUPSCollector(t, "OL CHRG")
+
+	// The node is observed before the UPS status was ever read.
+	c.ObserveNode("node", startedAgo(time.Minute*5))
+	c.mu.Lock()
+	pending := len(c.pending)
+	c.mu.Unlock()
+	if pending != 1 {
+		t.Fatalf("got %d pending nodes, want 1", pending)
+	}
+
+	assertEvents(t, downtimeEvents(t, c), "node", 1, 0)
+	// The pending event is only counted once.
+	assertEvents(t, downtimeEvents(t, c), "node", 1, 0)
+}
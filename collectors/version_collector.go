@@ -0,0 +1,233 @@
+package collectors
+
+import (
+	"encoding/binary"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type semVer struct {
+	major, minor, patch int
+}
+
+// parseSemVer parses versions such as "v1.114.6" or "1.114.6-rc", ignoring any pre-release suffix.
+func parseSemVer(s string) (semVer, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if index := strings.IndexAny(s, "-+"); index >= 0 {
+		s = s[:index]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return semVer{}, false
+	}
+	var numbers [3]int
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return semVer{}, false
+		}
+		numbers[i] = number
+	}
+	return semVer{major: numbers[0], minor: numbers[1], patch: numbers[2]}, true
+}
+
+func (v semVer) less(other semVer) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	if v.minor != other.minor {
+		return v.minor < other.minor
+	}
+	return v.patch < other.patch
+}
+
+// minorsBehind returns how many minor releases v is behind other, releases of an older major version count as one.
+func (v semVer) minorsBehind(other semVer) int {
+	switch {
+	case !v.less(other):
+		return 0
+	case v.major == other.major:
+		return other.minor - v.minor
+	default:
+		return other.minor + 1
+	}
+}
+
+// VersionCollector compares the running storagenode versions against the release information of the version server,
+// including whether the rollout of the suggested version has reached each node. The running versions are the ones
+// last observed by the NodeCollector.
+type VersionCollector struct {
+	versions *api.VersionClient
+	refresh  time.Duration
+	mu       sync.Mutex
+	cached   *models.StoragenodeVersions
+	updated  time.Time
+	// nodesMu is separate from mu, so observing nodes does not wait for the version server.
+	nodesMu sync.Mutex
+	nodes   map[string]models.NodeData
+	metrics map[string]*prometheus.Desc
+}
+
+func NewVersionCollector(versions *api.VersionClient, refresh time.Duration) *VersionCollector {
+	return &VersionCollector{
+		versions: versions,
+		refresh:  refresh,
+		nodes:    make(map[string]models.NodeData),
+		metrics: map[string]*prometheus.Desc{
+			"serverUp": prometheus.NewDesc(
+				"storj_version_server_up",
+				"Indicates if the last request to the version server succeeded",
+				nil,
+				nil,
+			),
+			"info": prometheus.NewDesc(
+				"storj_version_info",
+				"Running and allowed version of the node and the minimum and suggested version of the version server",
+				[]string{"node_id", "running", "allowed", "minimum", "suggested"},
+				nil,
+			),
+			"below": prometheus.NewDesc(
+				"storj_version_below",
+				"Indicates if the running version is older than the allowed, minimum or suggested version",
+				[]string{"node_id", "target"},
+				nil,
+			),
+			"minorsBehind": prometheus.NewDesc(
+				"storj_version_minor_releases_behind",
+				"Number of minor releases the running version is behind the allowed, minimum or suggested version",
+				[]string{"node_id", "target"},
+				nil,
+			),
+			"rolloutCursor": prometheus.NewDesc(
+				"storj_version_rollout_cursor_ratio",
+				"Share of nodes the rollout of the suggested version has reached",
+				nil,
+				nil,
+			),
+			"rolloutPosition": prometheus.NewDesc(
+				"storj_version_rollout_position_ratio",
+				"Position of the node in the rollout, the node is eligible once the rollout cursor reaches it",
+				[]string{"node_id"},
+				nil,
+			),
+			"rolloutEligible": prometheus.NewDesc(
+				"storj_version_rollout_eligible",
+				"Indicates if the rollout of the suggested version has reached the node",
+				[]string{"node_id"},
+				nil,
+			),
+			"updateOverdue": prometheus.NewDesc(
+				"storj_version_update_overdue",
+				"Indicates if the node runs a version below the minimum, or below the suggested version after the rollout reached it",
+				[]string{"node_id"},
+				nil,
+			),
+		},
+	}
+}
+
+func (c *VersionCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range c.metrics {
+		ch <- metric
+	}
+}
+
+func (c *VersionCollector) Collect(ch chan<- prometheus.Metric) {
+	versions, ok := c.storagenodeVersions()
+	ch <- prometheus.MustNewConstMetric(c.metrics["serverUp"], prometheus.GaugeValue, boolToFloat64(ok))
+	if versions == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.metrics["rolloutCursor"], prometheus.GaugeValue, rolloutRatio(versions.RolloutCursor))
+
+	c.nodesMu.Lock()
+	nodes := make(map[string]models.NodeData, len(c.nodes))
+	for nodeID, node := range c.nodes {
+		nodes[nodeID] = node
+	}
+	c.nodesMu.Unlock()
+
+	for nodeID, node := range nodes {
+		c.collectNodeVersion(ch, nodeID, &node, versions)
+	}
+}
+
+// ObserveNode keeps the node data for the version checks, a node that could not be reached is not checked.
+func (c *VersionCollector) ObserveNode(nodeID string, node *models.NodeData) {
+	c.nodesMu.Lock()
+	defer c.nodesMu.Unlock()
+
+	if node == nil {
+		delete(c.nodes, nodeID)
+		return
+	}
+	c.nodes[nodeID] = *node
+}
+
+// storagenodeVersions returns the cached release information, refreshing it when it is older than the refresh interval.
+// The last known information is kept when the version server is unavailable.
+func (c *VersionCollector) storagenodeVersions() (*models.StoragenodeVersions, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached != nil && time.Since(c.updated) < c.refresh {
+		return c.cached, true
+	}
+	versions, err := c.versions.Storagenode()
+	if err != nil {
+		log.Printf("Error collecting versions from %s: %v", c.versions.URL, err)
+		return c.cached, false
+	}
+	c.cached = &versions
+	c.updated = time.Now()
+	return c.cached, true
+}
+
+func (c *VersionCollector) collectNodeVersion(ch chan<- prometheus.Metric, nodeID string, node *models.NodeData, versions *models.StoragenodeVersions) {
+	ch <- prometheus.MustNewConstMetric(c.metrics["info"], prometheus.GaugeValue, 1, nodeID, node.Version, node.AllowedVersion, versions.Minimum, versions.Suggested)
+
+	running, ok := parseSemVer(node.Version)
+	if !ok {
+		log.Printf("Error parsing version %q of node [%s]", node.Version, nodeID)
+		return
+	}
+
+	below := make(map[string]bool)
+	targets := map[string]string{"allowed": node.AllowedVersion, "minimum": versions.Minimum, "suggested": versions.Suggested}
+	for target, version := range targets {
+		targetVersion, ok := parseSemVer(version)
+		if !ok {
+			continue
+		}
+		below[target] = running.less(targetVersion)
+		ch <- prometheus.MustNewConstMetric(c.metrics["below"], prometheus.GaugeValue, boolToFloat64(below[target]), nodeID, target)
+		ch <- prometheus.MustNewConstMetric(c.metrics["minorsBehind"], prometheus.GaugeValue, float64(running.minorsBehind(targetVersion)), nodeID, target)
+	}
+
+	position, err := api.RolloutPosition(nodeID, versions.RolloutSeed)
+	if err != nil {
+		log.Printf("Error computing rollout position of node [%s]: %v", nodeID, err)
+		return
+	}
+	eligible := api.RolloutEligible(position, versions.RolloutCursor)
+	ch <- prometheus.MustNewConstMetric(c.metrics["rolloutPosition"], prometheus.GaugeValue, rolloutRatio(position), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["rolloutEligible"], prometheus.GaugeValue, boolToFloat64(eligible), nodeID)
+	ch <- prometheus.MustNewConstMetric(c.metrics["updateOverdue"], prometheus.GaugeValue, boolToFloat64(below["minimum"] || (below["suggested"] && eligible)), nodeID)
+}
+
+// rolloutRatio maps a 32 byte rollout value onto 0..1 using its leading 8 bytes.
+func rolloutRatio(value []byte) float64 {
+	if len(value) < 8 {
+		return 0
+	}
+	return float64(binary.BigEndian.Uint64(value)) / math.MaxUint64
+}
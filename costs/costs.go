@@ -0,0 +1,41 @@
+package costs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the cost configuration:
+//
+//	electricity_price_per_kwh: 0.30
+//	nodes:
+//	  - name: node1
+//	    disk_price: 250
+//	    disk_amortization_months: 36
+//	    internet_monthly: 5
+//	    power_watts: 8
+func Load(path string) (models.CostConfig, error) {
+	var config models.CostConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("parsing %s failed: %w", path, err)
+	}
+
+	for i, node := range config.Nodes {
+		if node.NodeID == "" && node.Name == "" {
+			return config, fmt.Errorf("node %d in %s has neither node_id nor name", i+1, path)
+		}
+		if node.DiskPrice > 0 && node.DiskAmortizationMonths <= 0 {
+			return config, fmt.Errorf("node %d in %s has a disk_price but no disk_amortization_months", i+1, path)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,101 @@
+package identity
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// versionExtension is the certificate extension in which the storagenode records the identity version.
+var versionExtension = asn1.ObjectIdentifier{2, 999, 2, 1}
+
+// Load parses identity.cert and ca.cert in an identity directory. Private keys are never read.
+// The node ID is derived from the CA public key the same way the storagenode does.
+func Load(dir string) (models.Identity, error) {
+	var identity models.Identity
+
+	chain, err := readCertificates(filepath.Join(dir, "identity.cert"))
+	if err != nil {
+		return identity, err
+	}
+	if len(chain) < 2 {
+		return identity, errors.New("identity.cert must contain the leaf and CA certificates")
+	}
+	caChain, err := readCertificates(filepath.Join(dir, "ca.cert"))
+	if err != nil {
+		return identity, err
+	}
+
+	leaf, ca := chain[0], caChain[0]
+	identity.CertExpiresAt = leaf.NotAfter
+	identity.CAExpiresAt = ca.NotAfter
+	identity.ChainValid = bytes.Equal(chain[1].Raw, ca.Raw) && leaf.CheckSignatureFrom(ca) == nil
+
+	for _, extension := range ca.Extensions {
+		if extension.Id.Equal(versionExtension) && len(extension.Value) > 0 {
+			identity.Version = int(extension.Value[0])
+		}
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(ca.PublicKey)
+	if err != nil {
+		return identity, fmt.Errorf("encoding CA public key failed: %w", err)
+	}
+	first := sha256.Sum256(publicKey)
+	id := sha256.Sum256(first[:])
+	id[len(id)-1] = byte(identity.Version)
+
+	identity.NodeID = api.EncodeNodeID(id[:])
+	identity.Difficulty = difficulty(id[:])
+	return identity, nil
+}
+
+// difficulty counts the trailing zero bits of the node ID, not counting the version byte.
+func difficulty(id []byte) int {
+	zeroBits := 0
+	for i := len(id) - 2; i >= 0; i-- {
+		if id[i] != 0 {
+			return zeroBits + bits.TrailingZeros8(id[i])
+		}
+		zeroBits += 8
+	}
+	return zeroBits
+}
+
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s failed: %w", path, err)
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return certificates, nil
+}
@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+)
+
+// DockerSource follows the logs of a storagenode container through the Docker Engine API.
+type DockerSource struct {
+	Container string
+	client    *api.DockerClient
+	since     time.Time
+}
+
+func NewDockerSource(client *api.DockerClient, container string) *DockerSource {
+	return &DockerSource{
+		Container: container,
+		client:    client,
+		since:     time.Now(),
+	}
+}
+
+func (s *DockerSource) String() string {
+	return "container " + s.Container
+}
+
+func (s *DockerSource) Follow(handle func(line string)) error {
+	body, err := s.client.ContainerLogs(context.Background(), s.Container, s.since)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	// On reconnect continue after the last line read instead of replaying the container's history. Docker
+	// includes lines logged at the since time itself, so lines not newer than the last one are dropped.
+	return scanLines(body, func(line string) {
+		timestamp, message, found := strings.Cut(line, " ")
+		if !found {
+			return
+		}
+		at, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			handle(line)
+			return
+		}
+		if !at.After(s.since) {
+			return
+		}
+		s.since = at
+		handle(message)
+	})
+}
@@ -0,0 +1,101 @@
+package logs
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const pollInterval = time.Second
+
+// FileSource follows a log file like `tail -F`, surviving both rename based rotation and truncation.
+// Only lines written after the exporter started are read.
+type FileSource struct {
+	Path    string
+	started bool
+}
+
+func NewFileSource(path string) *FileSource {
+	return &FileSource{Path: path}
+}
+
+func (s *FileSource) String() string {
+	return s.Path
+}
+
+func (s *FileSource) Follow(handle func(line string)) error {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Skip the history on the first open, a rotated file is new and read from the start.
+	if !s.started {
+		if _, err := file.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+		s.started = true
+	}
+
+	opened, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	var partial strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		partial.WriteString(line)
+		if err == nil {
+			handle(partial.String())
+			partial.Reset()
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		time.Sleep(pollInterval)
+
+		current, err := os.Stat(s.Path)
+		if err == nil && !os.SameFile(opened, current) {
+			// The file was rotated. Everything written to the old file before the rename has been read.
+			return s.drain(reader, &partial, handle)
+		}
+
+		offset, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if info, err := file.Stat(); err == nil && info.Size() < offset {
+			// The file was truncated in place, start reading it again from the beginning.
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			reader.Reset(file)
+			partial.Reset()
+		}
+	}
+}
+
+func (s *FileSource) drain(reader *bufio.Reader, partial *strings.Builder, handle func(line string)) error {
+	for {
+		line, err := reader.ReadString('\n')
+		partial.WriteString(line)
+		if err != nil {
+			if partial.Len() > 0 {
+				handle(partial.String())
+			}
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		handle(partial.String())
+		partial.Reset()
+	}
+}
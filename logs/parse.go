@@ -0,0 +1,43 @@
+package logs
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// ParseLine parses a storagenode log line. Lines which are not in zap's console format are rejected.
+func ParseLine(line string) (models.LogEntry, bool) {
+	var entry models.LogEntry
+
+	parts := strings.Split(strings.TrimRight(line, "\r\n"), "\t")
+	if len(parts) < 3 {
+		return entry, false
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(parts[0]))
+	if err != nil {
+		return entry, false
+	}
+	entry.Time = timestamp
+	entry.Level = parts[1]
+	parts = parts[2:]
+
+	if last := parts[len(parts)-1]; strings.HasPrefix(last, "{") {
+		if err := json.Unmarshal([]byte(last), &entry.Fields); err == nil {
+			parts = parts[:len(parts)-1]
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+	case 1:
+		entry.Message = parts[0]
+	default:
+		entry.Logger = parts[0]
+		entry.Message = strings.Join(parts[1:], "\t")
+	}
+	return entry, true
+}
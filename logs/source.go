@@ -0,0 +1,55 @@
+package logs
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+const retryInterval = time.Second * 10
+
+// Source streams the log lines of a node. Follow blocks until the stream ends or fails.
+// A nil error means the stream ended and should be followed again immediately.
+type Source interface {
+	Follow(handle func(line string)) error
+	String() string
+}
+
+// Handler is implemented by collectors which derive metrics from log entries.
+type Handler interface {
+	HandleLog(nodeID string, entry *models.LogEntry)
+}
+
+// Tail follows the source forever, passing every parsed log entry to all handlers.
+// It reconnects after errors, so it is meant to be run in its own goroutine.
+func Tail(source Source, nodeID string, handlers []Handler) {
+	handle := func(line string) {
+		entry, ok := ParseLine(line)
+		if !ok {
+			return
+		}
+		for _, handler := range handlers {
+			handler.HandleLog(nodeID, &entry)
+		}
+	}
+
+	for {
+		// A stream that ended cleanly, e.g. after log rotation, is reopened right away.
+		if err := source.Follow(handle); err != nil {
+			log.Printf("Error following logs of node [%s] from %s: %v", nodeID, source, err)
+			time.Sleep(retryInterval)
+		}
+	}
+}
+
+func scanLines(r io.Reader, handle func(line string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		handle(scanner.Text())
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,19 @@
+package models
+
+// CostConfig holds the running costs of the nodes, prices are in dollars.
+type CostConfig struct {
+	ElectricityPricePerKWh float64     `yaml:"electricity_price_per_kwh"`
+	Nodes                  []NodeCosts `yaml:"nodes"`
+}
+
+// NodeCosts are the costs of a single node, selected by its node ID or its STORJ_NODE_%d_NAME.
+// PowerWatts is only used when no power meter measures the node. ElectricityPricePerKWh overrides the global price.
+type NodeCosts struct {
+	NodeID                 string   `yaml:"node_id"`
+	Name                   string   `yaml:"name"`
+	DiskPrice              float64  `yaml:"disk_price"`
+	DiskAmortizationMonths float64  `yaml:"disk_amortization_months"`
+	InternetMonthly        float64  `yaml:"internet_monthly"`
+	PowerWatts             float64  `yaml:"power_watts"`
+	ElectricityPricePerKWh *float64 `yaml:"electricity_price_per_kwh"`
+}
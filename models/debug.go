@@ -0,0 +1,18 @@
+package models
+
+// DebugMetric is a single sample read from the storagenode debug endpoint.
+// Histograms and summaries are flattened into their _sum, _count, _bucket and quantile samples.
+type DebugMetric struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+	Type   DebugMetricType
+}
+
+type DebugMetricType int
+
+const (
+	DebugUntyped DebugMetricType = iota
+	DebugCounter
+	DebugGauge
+)
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DiskHealth is the SMART status of a disk as reported by smartctl. Counters not reported
+// by the disk, e.g. sector counts of NVMe drives, are nil.
+type DiskHealth struct {
+	Device               string
+	Model                string
+	Serial               string
+	Passed               bool
+	TemperatureCelsius   *float64
+	PowerOnHours         *float64
+	ReallocatedSectors   *float64
+	PendingSectors       *float64
+	OfflineUncorrectable *float64
+	MediaErrors          *float64
+	PercentageUsed       *float64
+	ReadAt               time.Time
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Identity describes the certificates in a storagenode identity directory.
+type Identity struct {
+	NodeID        string
+	Version       int
+	Difficulty    int
+	CertExpiresAt time.Time
+	CAExpiresAt   time.Time
+	// ChainValid is false if identity.cert is not signed by the CA in ca.cert.
+	ChainValid bool
+}
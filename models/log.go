@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// LogEntry is a single line of storagenode log output in zap's console format:
+// "<time>\t<level>\t<logger>\t<message>\t<json fields>".
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Logger  string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Field returns a log field as a string, or an empty string if it is missing.
+func (e *LogEntry) Field(name string) string {
+	value, ok := e.Fields[name]
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// NumberField returns a numeric log field, or 0 if it is missing or not a number.
+func (e *LogEntry) NumberField(name string) float64 {
+	value, _ := e.Fields[name].(float64)
+	return value
+}
+
+// PieceFailure is a failed audit or repair download taken from the node logs.
+type PieceFailure struct {
+	NodeID      string    `json:"nodeId"`
+	Time        time.Time `json:"time"`
+	SatelliteID string    `json:"satelliteId"`
+	PieceID     string    `json:"pieceId"`
+	Action      string    `json:"action"`
+	ErrorClass  string    `json:"errorClass"`
+	Error       string    `json:"error"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// MultinodeNode is a node as listed by the multinode dashboard.
+type MultinodeNode struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Version       string    `json:"version"`
+	LastContact   time.Time `json:"lastContact"`
+	DiskSpaceUsed int64     `json:"diskSpaceUsed"`
+	DiskSpaceLeft int64     `json:"diskSpaceLeft"`
+	BandwidthUsed int64     `json:"bandwidthUsed"`
+	TotalEarned   int64     `json:"totalEarned"`
+	Status        string    `json:"status"`
+}
+
+type MultinodePayoutSummary struct {
+	TotalEarned int64                 `json:"totalEarned"`
+	TotalHeld   int64                 `json:"totalHeld"`
+	TotalPaid   int64                 `json:"totalPaid"`
+	NodeSummary []MultinodeNodePayout `json:"nodeSummary"`
+}
+
+type MultinodeNodePayout struct {
+	NodeID   string `json:"nodeId"`
+	NodeName string `json:"nodeName"`
+	Held     int64  `json:"held"`
+	Paid     int64  `json:"paid"`
+}
+
+type MultinodeDiskSpace struct {
+	Allocated  int64 `json:"allocated"`
+	UsedPieces int64 `json:"usedPieces"`
+	UsedTrash  int64 `json:"usedTrash"`
+	Free       int64 `json:"free"`
+	Available  int64 `json:"available"`
+	Overused   int64 `json:"overused"`
+}
@@ -1,12 +1,15 @@
 package models
 
+import "time"
+
 type NodeData struct {
-	NodeID           string `json:"nodeID"`
-	Wallet           string `json:"wallet"`
-	UpToDate         bool   `json:"upToDate"`
-	Version          string `json:"version"`
-	AllowedVersion   string `json:"allowedVersion"`
-	QuicStatus       string `json:"quicStatus"`
+	NodeID           string   `json:"nodeID"`
+	Wallet           string   `json:"wallet"`
+	WalletFeatures   []string `json:"walletFeatures"`
+	UpToDate         bool     `json:"upToDate"`
+	Version          string   `json:"version"`
+	AllowedVersion   string   `json:"allowedVersion"`
+	QuicStatus       string   `json:"quicStatus"`
 	DiskSpace        DiskSpace
 	Bandwidth        Bandwidth
 	Satellites       []Satellite `json:"satellites"`
@@ -17,11 +20,13 @@ type NodeData struct {
 }
 
 type Satellite struct {
-	ID                 string  `json:"id"`
-	URL                string  `json:"url"`
-	Disqualified       *string `json:"disqualified"`
-	Suspended          *string `json:"suspended"`
-	CurrentStorageUsed int64   `json:"currentStorageUsed"`
+	ID                 string     `json:"id"`
+	URL                string     `json:"url"`
+	Disqualified       *time.Time `json:"disqualified"`
+	Suspended          *time.Time `json:"suspended"`
+	OfflineSuspended   *time.Time `json:"offlineSuspended"`
+	VettedAt           *time.Time `json:"vettedAt"`
+	CurrentStorageUsed int64      `json:"currentStorageUsed"`
 }
 
 type DiskSpace struct {
@@ -35,3 +40,15 @@ type Bandwidth struct {
 	Used      int64 `json:"used"`
 	Available int64 `json:"available"`
 }
+
+// NodeConfig holds the settings of the storagenode config.yaml the exporter inspects.
+type NodeConfig struct {
+	ExternalAddress    string
+	Wallet             string
+	WalletFeatures     []string
+	Email              string
+	AllocatedDiskSpace int64
+	DebugAddress       string
+	PrivateAddress     string
+	StoragePath        string
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// BandwidthUsage is the bandwidth used for one satellite and piece action, summed over the current hour, day and month.
+type BandwidthUsage struct {
+	SatelliteID string
+	Action      string
+	Hour        int64
+	Day         int64
+	Month       int64
+}
+
+// PieceExpirationForecast is the number of a satellite's pieces that expire within a window from now.
+// piece_expiration.db does not record piece sizes, so the bytes to be freed are unknown.
+type PieceExpirationForecast struct {
+	SatelliteID string
+	Window      string
+	Pieces      int64
+}
+
+// UsedSpacePrefix is the used space of one piece prefix directory, saved by the used-space filewalker
+// so it can resume after a restart.
+type UsedSpacePrefix struct {
+	SatelliteID      string
+	Prefix           string
+	TotalBytes       int64
+	TotalContentSize int64
+	Pieces           int64
+	UpdatedAt        time.Time
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type NotificationsResponse struct {
+	Page        NotificationsPage `json:"page"`
+	UnreadCount int               `json:"unreadCount"`
+	TotalCount  int               `json:"totalCount"`
+}
+
+type NotificationsPage struct {
+	Notifications []Notification `json:"notifications"`
+	Offset        int            `json:"offset"`
+	Limit         int            `json:"limit"`
+	CurrentPage   int            `json:"currentPage"`
+	PageCount     int            `json:"pageCount"`
+}
+
+type Notification struct {
+	ID        string     `json:"id"`
+	SenderID  string     `json:"senderId"`
+	Type      int        `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"readAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// PowerReading is a reading of a power meter. EnergyKWh is the lifetime energy counter of the meter, nil if it has none.
+type PowerReading struct {
+	Watts     float64
+	EnergyKWh *float64
+}
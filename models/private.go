@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// DashboardData is the dashboard response of the storagenode private API.
+type DashboardData struct {
+	NodeID           []byte
+	NodeConnections  int64
+	InternalAddress  string
+	ExternalAddress  string
+	DashboardAddress string
+	Stats            StatSummary
+	Uptime           time.Duration
+	LastPinged       time.Time
+}
+
+type StatSummary struct {
+	UsedSpace          int64
+	AvailableSpace     int64
+	UsedIngress        int64
+	UsedEgress         int64
+	UsedBandwidth      int64
+	AvailableBandwidth int64
+}
+
+// ExitProgress is the graceful exit status of the node on a single satellite.
+type ExitProgress struct {
+	DomainName      string
+	SatelliteID     string
+	PercentComplete float64
+	Successful      bool
+	// FinishedAt is taken from the signed completion receipt and is zero while the exit is in progress.
+	FinishedAt time.Time
+}
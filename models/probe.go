@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SatelliteProbe is the result of probing a satellite address from the exporter host.
+type SatelliteProbe struct {
+	SatelliteID string
+	Address     string
+	ProbedAt    time.Time
+	// Success and Latency are keyed by protocol, Latency only holds the successful probes.
+	Success map[string]bool
+	Latency map[string]time.Duration
+}
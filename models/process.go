@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ProcessStats is the resource usage of a storagenode process or container.
+type ProcessStats struct {
+	CPUSeconds  float64
+	MemoryBytes int64
+	// OpenFDs is -1 when the number of open file descriptors is unknown, as for containers.
+	OpenFDs    int64
+	ReadBytes  int64
+	WriteBytes int64
+	// HasIO is false when the I/O counters could not be read, e.g. without permission for /proc/<pid>/io.
+	HasIO     bool
+	StartedAt time.Time
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// FilesystemStats describes the filesystem a node's storage directory is mounted on.
+type FilesystemStats struct {
+	Size       uint64
+	Free       uint64
+	Available  uint64
+	Inodes     uint64
+	InodesFree uint64
+}
+
+// DirectoryUsage is the measured size of the directories in a node's storage directory.
+// Satellites and Trash are updated while a scan progresses, Directories once a scan completes.
+type DirectoryUsage struct {
+	Directories map[string]DirectorySize
+	Satellites  map[SatelliteDirectory]DirectorySize
+	Trash       map[TrashDirectory]DirectorySize
+	ScannedAt   time.Time
+	Duration    time.Duration
+}
+
+type DirectorySize struct {
+	Bytes int64
+	Files int64
+}
+
+// SatelliteDirectory is the blobs or trash directory of a single satellite.
+type SatelliteDirectory struct {
+	SatelliteID string
+	Directory   string
+}
+
+// TrashDirectory is a dated trash directory of a single satellite, emptied once the trash expires.
+type TrashDirectory struct {
+	SatelliteID string
+	Date        time.Time
+}
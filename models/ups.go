@@ -0,0 +1,13 @@
+package models
+
+// UPSStatus is the state of a UPS as reported by a NUT server. Values the UPS driver does not provide are nil.
+type UPSStatus struct {
+	Name           string
+	Status         string
+	OnBattery      bool
+	LowBattery     bool
+	Charging       bool
+	BatteryCharge  *float64
+	BatteryRuntime *float64
+	Load           *float64
+}
@@ -0,0 +1,10 @@
+package models
+
+// StoragenodeVersions is the storagenode release information published by the version server.
+type StoragenodeVersions struct {
+	Minimum   string
+	Suggested string
+	// RolloutSeed and RolloutCursor are the raw 32 byte values, nodes whose hashed ID is at or below the cursor should update.
+	RolloutSeed   []byte
+	RolloutCursor []byte
+}
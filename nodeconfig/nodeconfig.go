@@ -0,0 +1,97 @@
+package nodeconfig
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/akash329d/storj_exporter/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads a storagenode config.yaml, which uses flat dotted keys such as "operator.wallet".
+func Load(path string) (models.NodeConfig, error) {
+	var config models.NodeConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return config, fmt.Errorf("parsing %s failed: %w", path, err)
+	}
+
+	config.ExternalAddress = stringValue(values["contact.external-address"])
+	config.Wallet = stringValue(values["operator.wallet"])
+	config.Email = stringValue(values["operator.email"])
+	config.DebugAddress = stringValue(values["debug.addr"])
+	config.PrivateAddress = stringValue(values["server.private-address"])
+	config.StoragePath = stringValue(values["storage.path"])
+
+	switch features := values["operator.wallet-features"].(type) {
+	case []interface{}:
+		for _, feature := range features {
+			config.WalletFeatures = append(config.WalletFeatures, stringValue(feature))
+		}
+	case string:
+		for _, feature := range strings.Split(features, ",") {
+			if feature = strings.TrimSpace(feature); feature != "" {
+				config.WalletFeatures = append(config.WalletFeatures, feature)
+			}
+		}
+	}
+
+	if allocated := stringValue(values["storage.allocated-disk-space"]); allocated != "" {
+		config.AllocatedDiskSpace, err = ParseSize(allocated)
+		if err != nil {
+			return config, fmt.Errorf("parsing storage.allocated-disk-space of %s failed: %w", path, err)
+		}
+	}
+	return config, nil
+}
+
+func stringValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
+
+// Size units as understood by the storagenode, decimal for "TB" and binary for "TiB".
+var sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"eb":  1e18,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+	"eib": 1 << 60,
+}
+
+// ParseSize parses a size such as "2.00 TB" or "500GiB" into bytes.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	split := strings.LastIndexAny(s, "0123456789.") + 1
+	number, unit := strings.TrimSpace(s[:split]), strings.ToLower(strings.TrimSpace(s[split:]))
+
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", unit)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(value * multiplier), nil
+}
@@ -0,0 +1,94 @@
+package nodedb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Piece actions as stored in the action column, see pb.PieceAction.
+var pieceActions = map[int]string{
+	0: "INVALID",
+	1: "PUT",
+	2: "GET",
+	3: "GET_AUDIT",
+	4: "GET_REPAIR",
+	5: "PUT_REPAIR",
+	6: "DELETE",
+	7: "PUT_GRACEFUL_EXIT",
+}
+
+// BandwidthDB reads bandwidth.db, which holds the recent bandwidth usage and its hourly rollups.
+type BandwidthDB struct {
+	db *sql.DB
+}
+
+func OpenBandwidthDB(dir string) (*BandwidthDB, error) {
+	db, err := Open(dir, "bandwidth.db")
+	if err != nil {
+		return nil, err
+	}
+	return &BandwidthDB{db: db}, nil
+}
+
+// Usage returns the bandwidth used per satellite and action in the current hour, day and month (UTC).
+func (b *BandwidthDB) Usage(now time.Time) ([]models.BandwidthUsage, error) {
+	var tables []string
+	for _, table := range []string{"bandwidth_usage", "bandwidth_usage_rollups"} {
+		exists, err := tableExists(b.db, table)
+		if err != nil {
+			return nil, fmt.Errorf("reading bandwidth.db schema failed: %w", err)
+		}
+		if exists {
+			tables = append(tables, "SELECT interval_start, satellite_id, action, amount FROM "+table)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("bandwidth.db contains no known bandwidth tables")
+	}
+
+	now = now.UTC()
+	hour := now.Truncate(time.Hour)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	rows, err := b.db.Query(`
+		SELECT satellite_id, action,
+			SUM(CASE WHEN datetime(interval_start) >= ? THEN amount ELSE 0 END),
+			SUM(CASE WHEN datetime(interval_start) >= ? THEN amount ELSE 0 END),
+			SUM(amount)
+		FROM (`+strings.Join(tables, " UNION ALL ")+`)
+		WHERE datetime(interval_start) >= ?
+		GROUP BY satellite_id, action`,
+		sqliteTime(hour), sqliteTime(day), sqliteTime(month),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying bandwidth.db failed: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []models.BandwidthUsage
+	for rows.Next() {
+		var satelliteID []byte
+		var action int
+		var data models.BandwidthUsage
+		if err := rows.Scan(&satelliteID, &action, &data.Hour, &data.Day, &data.Month); err != nil {
+			return nil, fmt.Errorf("reading bandwidth.db failed: %w", err)
+		}
+		data.SatelliteID = api.EncodeNodeID(satelliteID)
+		data.Action = pieceActionName(action)
+		usage = append(usage, data)
+	}
+	return usage, rows.Err()
+}
+
+func pieceActionName(action int) string {
+	if name, ok := pieceActions[action]; ok {
+		return name
+	}
+	return fmt.Sprintf("ACTION_%d", action)
+}
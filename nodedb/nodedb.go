@@ -0,0 +1,65 @@
+package nodedb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Open opens one of the storagenode SQLite databases, e.g. "bandwidth.db", in dir without write access.
+// The storagenode runs its databases in WAL mode, where readers never block the writer, and the connection
+// is closed shortly after each scrape so the node can checkpoint the WAL.
+func Open(dir, name string) (*sql.DB, error) {
+	path := filepath.Join(dir, name)
+	query := url.Values{}
+	query.Set("mode", "ro")
+	query.Add("_pragma", "query_only(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+
+	db, err := sql.Open("sqlite", (&url.URL{Scheme: "file", Path: path, RawQuery: query.Encode()}).String())
+	if err != nil {
+		return nil, fmt.Errorf("opening %s failed: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxIdleTime(time.Second * 10)
+	return db, nil
+}
+
+// tableExists checks the schema, which differs between storagenode versions.
+func tableExists(db *sql.DB, table string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	return count > 0, err
+}
+
+// sqliteTime formats a time the way SQLite's datetime() function returns it, for comparisons in queries.
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// storedTime formats a time the way the storagenode's SQLite driver stores it in UTC, so a TIMESTAMP column can
+// be compared as text and its index used.
+func storedTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999999-07:00")
+}
+
+// parseTime converts a TIMESTAMP column, which the driver returns either parsed or as the text the node stored.
+func parseTime(value interface{}) time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return v
+	case string:
+		for _, layout := range []string{"2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t
+			}
+		}
+	case []byte:
+		return parseTime(string(v))
+	}
+	return time.Time{}
+}
@@ -0,0 +1,93 @@
+package nodedb
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// ExpirationWindows are the forecast windows, labeled by their name.
+var ExpirationWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"1d", time.Hour * 24},
+	{"7d", time.Hour * 24 * 7},
+	{"30d", time.Hour * 24 * 30},
+}
+
+// PieceExpirationDB reads piece_expiration.db, which schedules the deletion of pieces uploaded with a TTL.
+// Newer storagenode versions keep expirations in flat files under the storage directory instead,
+// on those nodes the table is no longer written and the forecast stays empty.
+type PieceExpirationDB struct {
+	db *sql.DB
+}
+
+func OpenPieceExpirationDB(dir string) (*PieceExpirationDB, error) {
+	db, err := Open(dir, "piece_expiration.db")
+	if err != nil {
+		return nil, err
+	}
+	return &PieceExpirationDB{db: db}, nil
+}
+
+// Forecast returns the number of pieces of each satellite that expire within the expiration windows from now,
+// including pieces that already expired but have not been deleted yet.
+func (p *PieceExpirationDB) Forecast(now time.Time) ([]models.PieceExpirationForecast, error) {
+	// The node stores expirations in UTC, compared as text against the same format the index on piece_expiration is used.
+	var columns []string
+	var args []interface{}
+	for _, window := range ExpirationWindows {
+		columns = append(columns, "SUM(CASE WHEN piece_expiration <= ? THEN 1 ELSE 0 END)")
+		args = append(args, storedTime(now.Add(window.Duration)))
+	}
+	args = append(args, storedTime(now.Add(ExpirationWindows[len(ExpirationWindows)-1].Duration)))
+
+	rows, err := p.db.Query(`
+		SELECT satellite_id, `+strings.Join(columns, ", ")+`
+		FROM piece_expirations
+		WHERE piece_expiration <= ?
+		GROUP BY satellite_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying piece_expiration.db failed: %w", err)
+	}
+	defer rows.Close()
+
+	var forecasts []models.PieceExpirationForecast
+	for rows.Next() {
+		var satelliteID []byte
+		values := make([]sql.NullInt64, len(columns))
+		dest := []interface{}{&satelliteID}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("reading piece_expiration.db failed: %w", err)
+		}
+
+		for i, window := range ExpirationWindows {
+			forecasts = append(forecasts, models.PieceExpirationForecast{
+				SatelliteID: api.EncodeNodeID(satelliteID),
+				Window:      window.Name,
+				Pieces:      values[i].Int64,
+			})
+		}
+	}
+	return forecasts, rows.Err()
+}
+
+// LatestExpiration returns the latest expiration in the table, zero if it is empty. A table without future
+// expirations is no longer written, as on storagenode versions keeping expirations in flat files.
+func (p *PieceExpirationDB) LatestExpiration() (time.Time, error) {
+	var latest interface{}
+	if err := p.db.QueryRow("SELECT MAX(piece_expiration) FROM piece_expirations").Scan(&latest); err != nil {
+		return time.Time{}, fmt.Errorf("querying piece_expiration.db failed: %w", err)
+	}
+	return parseTime(latest), nil
+}
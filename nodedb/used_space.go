@@ -0,0 +1,48 @@
+package nodedb
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// UsedSpacePrefixes is the number of piece prefix directories of a satellite, two base32 characters.
+const UsedSpacePrefixes = 32 * 32
+
+// UsedSpaceDB reads used_space_per_prefix.db, the progress cache of the used-space filewalker.
+type UsedSpaceDB struct {
+	db *sql.DB
+}
+
+func OpenUsedSpaceDB(dir string) (*UsedSpaceDB, error) {
+	db, err := Open(dir, "used_space_per_prefix.db")
+	if err != nil {
+		return nil, err
+	}
+	return &UsedSpaceDB{db: db}, nil
+}
+
+// Prefixes returns every prefix recorded by the used-space filewalker.
+func (u *UsedSpaceDB) Prefixes() ([]models.UsedSpacePrefix, error) {
+	rows, err := u.db.Query("SELECT satellite_id, piece_prefix, total_bytes, total_content_size, piece_counts, updated_at FROM used_space_per_prefix")
+	if err != nil {
+		return nil, fmt.Errorf("querying used_space_per_prefix.db failed: %w", err)
+	}
+	defer rows.Close()
+
+	var prefixes []models.UsedSpacePrefix
+	for rows.Next() {
+		var satelliteID []byte
+		var updatedAt interface{}
+		var prefix models.UsedSpacePrefix
+		if err := rows.Scan(&satelliteID, &prefix.Prefix, &prefix.TotalBytes, &prefix.TotalContentSize, &prefix.Pieces, &updatedAt); err != nil {
+			return nil, fmt.Errorf("reading used_space_per_prefix.db failed: %w", err)
+		}
+		prefix.SatelliteID = api.EncodeNodeID(satelliteID)
+		prefix.UpdatedAt = parseTime(updatedAt)
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, rows.Err()
+}
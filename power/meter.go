@@ -0,0 +1,119 @@
+package power
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Meter reads a smart plug over HTTP. The JSON formats of Shelly (Gen1 /status or /meter/0, Gen2 /rpc/Switch.GetStatus?id=0)
+// and Tasmota (/cm?cmnd=Status%208) are recognized from the response.
+type Meter struct {
+	Name       string
+	URL        string
+	httpClient *http.Client
+}
+
+func NewMeter(name, url string) *Meter {
+	return &Meter{
+		Name: name,
+		URL:  url,
+		httpClient: &http.Client{
+			Timeout: time.Second * 10,
+		},
+	}
+}
+
+type meterResponse struct {
+	// Shelly Gen1 /meter/0, total in watt-minutes.
+	Power *float64 `json:"power"`
+	Total *float64 `json:"total"`
+	// Shelly Gen1 /status.
+	Meters []struct {
+		Power float64  `json:"power"`
+		Total *float64 `json:"total"`
+	} `json:"meters"`
+	// Shelly Gen2, total in watt-hours.
+	APower  *float64 `json:"apower"`
+	AEnergy *struct {
+		Total float64 `json:"total"`
+	} `json:"aenergy"`
+	// Tasmota, total in kWh.
+	StatusSNS *struct {
+		Energy *struct {
+			Power channelSum  `json:"Power"`
+			Total *channelSum `json:"Total"`
+		} `json:"ENERGY"`
+	} `json:"StatusSNS"`
+}
+
+// channelSum is a Tasmota energy value, a number or an array with one number per channel on devices
+// with several channels. The channels are summed up.
+type channelSum float64
+
+func (s *channelSum) UnmarshalJSON(data []byte) error {
+	var channels []float64
+	if err := json.Unmarshal(data, &channels); err != nil {
+		var value float64
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		channels = []float64{value}
+	}
+
+	*s = 0
+	for _, channel := range channels {
+		*s += channelSum(channel)
+	}
+	return nil
+}
+
+func (m *Meter) Read() (models.PowerReading, error) {
+	var reading models.PowerReading
+
+	resp, err := m.httpClient.Get(m.URL)
+	if err != nil {
+		return reading, fmt.Errorf("Power meter request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return reading, fmt.Errorf("Power meter request failed with status code: %d", resp.StatusCode)
+	}
+
+	var data meterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return reading, fmt.Errorf("Power meter response invalid: %w", err)
+	}
+
+	scaled := func(value *float64, factor float64) *float64 {
+		if value == nil {
+			return nil
+		}
+		kwh := *value * factor
+		return &kwh
+	}
+
+	switch {
+	case data.APower != nil:
+		reading.Watts = *data.APower
+		if data.AEnergy != nil {
+			reading.EnergyKWh = scaled(&data.AEnergy.Total, 1.0/1000)
+		}
+	case data.StatusSNS != nil && data.StatusSNS.Energy != nil:
+		reading.Watts = float64(data.StatusSNS.Energy.Power)
+		reading.EnergyKWh = (*float64)(data.StatusSNS.Energy.Total)
+	case len(data.Meters) > 0:
+		reading.Watts = data.Meters[0].Power
+		reading.EnergyKWh = scaled(data.Meters[0].Total, 1.0/60000)
+	case data.Power != nil:
+		reading.Watts = *data.Power
+		reading.EnergyKWh = scaled(data.Total, 1.0/60000)
+	default:
+		return reading, errors.New("Power meter response has no known format")
+	}
+	return reading, nil
+}
@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"net"
+	"time"
+)
+
+// QUIC servers answer an Initial packet of an unknown version with a version negotiation packet,
+// which proves the UDP port is reachable without completing a handshake.
+const (
+	quicMinInitialSize = 1200
+	quicProbeVersion   = 0x1a2a3a4a // reserved version, never supported by servers
+)
+
+// TCP connects to the address and returns the time the connection took.
+func TCP(address string, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	conn.Close()
+	return rtt, nil
+}
+
+// QUIC sends a QUIC Initial packet with an unsupported version to the address and returns the time
+// until the server answered with a version negotiation packet.
+func QUIC(address string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	packet, err := quicProbePacket()
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Write(packet); err != nil {
+		return 0, err
+	}
+
+	response := make([]byte, 1500)
+	for {
+		n, err := conn.Read(response)
+		if err != nil {
+			return 0, err
+		}
+		// Version negotiation packets have a long header and version 0.
+		if n >= 5 && response[0]&0x80 != 0 && response[1] == 0 && response[2] == 0 && response[3] == 0 && response[4] == 0 {
+			return time.Since(start), nil
+		}
+		if n > 0 && response[0]&0x80 == 0 {
+			return 0, errors.New("unexpected QUIC response")
+		}
+	}
+}
+
+func quicProbePacket() ([]byte, error) {
+	connectionIDs := make([]byte, 16)
+	if _, err := rand.Read(connectionIDs); err != nil {
+		return nil, err
+	}
+
+	packet := make([]byte, 0, quicMinInitialSize)
+	packet = append(packet, 0xc0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(packet[1:], quicProbeVersion)
+	packet = append(packet, 8)
+	packet = append(packet, connectionIDs[:8]...)
+	packet = append(packet, 8)
+	packet = append(packet, connectionIDs[8:]...)
+	// Servers ignore Initial packets below the minimum datagram size, pad with zeros.
+	return packet[:quicMinInitialSize], nil
+}
+
+// TLS connects to the address and completes a TLS handshake, returning the time of the whole handshake
+// including the TCP connect. Storj peers use self-signed certificates, they are not verified.
+func TLS(address string, timeout time.Duration) (time.Duration, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	conn.Close()
+	return rtt, nil
+}
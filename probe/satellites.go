@@ -0,0 +1,92 @@
+package probe
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// SatelliteProber periodically probes satellite addresses in the background. Nodes usually share
+// satellites, each address is only probed once no matter how many nodes use it.
+type SatelliteProber struct {
+	satellites map[string]string
+	interval   time.Duration
+	timeout    time.Duration
+
+	mu      sync.Mutex
+	results []models.SatelliteProbe
+}
+
+// NewSatelliteProber creates a prober for the given satellite addresses keyed by satellite ID.
+func NewSatelliteProber(satellites map[string]string, interval time.Duration, timeout time.Duration) *SatelliteProber {
+	return &SatelliteProber{
+		satellites: satellites,
+		interval:   interval,
+		timeout:    timeout,
+	}
+}
+
+// SatelliteAddress returns the host and port of a satellite URL, which may be prefixed with the satellite ID as in "id@host:port".
+func SatelliteAddress(url string) string {
+	if index := strings.LastIndex(url, "@"); index >= 0 {
+		url = url[index+1:]
+	}
+	return url
+}
+
+// Results returns the results of the last round of probes.
+func (p *SatelliteProber) Results() []models.SatelliteProbe {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.results
+}
+
+// Run probes the satellites forever, it is meant to be run in its own goroutine.
+func (p *SatelliteProber) Run() {
+	for {
+		p.probe()
+		time.Sleep(p.interval)
+	}
+}
+
+func (p *SatelliteProber) probe() {
+	probes := map[string]func(string, time.Duration) (time.Duration, error){
+		"tcp": TCP,
+		"tls": TLS,
+	}
+
+	var wg sync.WaitGroup
+	results := make([]models.SatelliteProbe, 0, len(p.satellites))
+	var resultsMu sync.Mutex
+	for satelliteID, address := range p.satellites {
+		wg.Add(1)
+		go func(satelliteID, address string) {
+			defer wg.Done()
+			result := models.SatelliteProbe{
+				SatelliteID: satelliteID,
+				Address:     address,
+				ProbedAt:    time.Now(),
+				Success:     make(map[string]bool, len(probes)),
+				Latency:     make(map[string]time.Duration, len(probes)),
+			}
+			for protocol, probeAddress := range probes {
+				latency, err := probeAddress(address, p.timeout)
+				result.Success[protocol] = err == nil
+				if err == nil {
+					result.Latency[protocol] = latency
+				}
+			}
+
+			resultsMu.Lock()
+			results = append(results, result)
+			resultsMu.Unlock()
+		}(satelliteID, address)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.results = results
+	p.mu.Unlock()
+}
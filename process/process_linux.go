@@ -0,0 +1,140 @@
+//go:build linux
+
+package process
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc. It is 100 on every supported architecture.
+const clockTicks = 100
+
+// Find returns the PID of the oldest process with the given name. The storagenode runs its lazy filewalkers
+// as subprocesses of the same name, the oldest one is the node itself.
+func Find(name string) (int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, err
+	}
+
+	found, oldest := 0, uint64(0)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "comm"))
+		if err != nil || strings.TrimSpace(string(comm)) != name {
+			continue
+		}
+		fields, err := statFields(pid)
+		if err != nil {
+			continue
+		}
+		startTicks, _ := strconv.ParseUint(fields[19], 10, 64)
+		if found == 0 || startTicks < oldest {
+			found, oldest = pid, startTicks
+		}
+	}
+	if found == 0 {
+		return 0, fmt.Errorf("no process named %s found", name)
+	}
+	return found, nil
+}
+
+// Stats reads the resource usage of a process from /proc.
+func Stats(pid int) (models.ProcessStats, error) {
+	stats := models.ProcessStats{OpenFDs: -1}
+
+	fields, err := statFields(pid)
+	if err != nil {
+		return stats, err
+	}
+	utime, _ := strconv.ParseFloat(fields[11], 64)
+	stime, _ := strconv.ParseFloat(fields[12], 64)
+	stats.CPUSeconds = (utime + stime) / clockTicks
+	rssPages, _ := strconv.ParseInt(fields[21], 10, 64)
+	stats.MemoryBytes = rssPages * int64(os.Getpagesize())
+
+	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
+	if bootTime, err := bootTime(); err == nil {
+		stats.StartedAt = bootTime.Add(time.Duration(startTicks) * time.Second / clockTicks)
+	}
+
+	if fds, err := os.ReadDir(fmt.Sprintf("/proc/%d/fd", pid)); err == nil {
+		stats.OpenFDs = int64(len(fds))
+	}
+
+	if io, err := readKeyValues(fmt.Sprintf("/proc/%d/io", pid)); err == nil {
+		stats.ReadBytes = io["read_bytes"]
+		stats.WriteBytes = io["write_bytes"]
+		stats.HasIO = true
+	}
+	return stats, nil
+}
+
+// statFields returns the fields of /proc/<pid>/stat after the command name, starting with the state.
+// The command name is in parentheses and may contain spaces, so the fields are split after its closing parenthesis.
+func statFields(pid int) ([]string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+	index := strings.LastIndexByte(string(data), ')')
+	if index < 0 {
+		return nil, errors.New("invalid stat format")
+	}
+	fields := strings.Fields(string(data[index+1:]))
+	if len(fields) < 22 {
+		return nil, errors.New("invalid stat format")
+	}
+	return fields, nil
+}
+
+func bootTime() (time.Time, error) {
+	file, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "btime ") {
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "btime "), 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	return time.Time{}, errors.New("btime not found in /proc/stat")
+}
+
+func readKeyValues(path string) (map[string]int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]int64)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		number, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err == nil {
+			values[key] = number
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package process
+
+import (
+	"errors"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Find is only implemented on Linux.
+func Find(name string) (int, error) {
+	return 0, errors.New("process lookup is not supported on this platform")
+}
+
+// Stats is only implemented on Linux.
+func Stats(pid int) (models.ProcessStats, error) {
+	return models.ProcessStats{}, errors.New("process stats are not supported on this platform")
+}
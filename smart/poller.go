@@ -0,0 +1,55 @@
+package smart
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Poller runs smartctl for a set of devices in the background. SMART data changes slowly and
+// reading it can take seconds per disk, so it is not read on every scrape.
+type Poller struct {
+	smartctl string
+	devices  []string
+	interval time.Duration
+
+	mu      sync.Mutex
+	results map[string]models.DiskHealth
+}
+
+func NewPoller(smartctl string, devices []string, interval time.Duration) *Poller {
+	return &Poller{
+		smartctl: smartctl,
+		devices:  devices,
+		interval: interval,
+		results:  make(map[string]models.DiskHealth),
+	}
+}
+
+// Health returns the last SMART data read from the device.
+func (p *Poller) Health(device string) (models.DiskHealth, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	health, ok := p.results[device]
+	return health, ok
+}
+
+// Run polls the devices forever, it is meant to be run in its own goroutine.
+// The last result of a device is kept while it is in standby or smartctl fails.
+func (p *Poller) Run() {
+	for {
+		for _, device := range p.devices {
+			health, err := Run(p.smartctl, device)
+			if err != nil {
+				log.Printf("Error reading SMART data: %v", err)
+				continue
+			}
+			p.mu.Lock()
+			p.results[device] = health
+			p.mu.Unlock()
+		}
+		time.Sleep(p.interval)
+	}
+}
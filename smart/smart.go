@@ -0,0 +1,130 @@
+package smart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// ATA SMART attributes exported from the attribute table.
+const (
+	attributeReallocatedSectors   = 5
+	attributePendingSectors       = 197
+	attributeOfflineUncorrectable = 198
+)
+
+// Bits of the smartctl exit status that mean no SMART data was read at all.
+const exitStatusFatal = 0x3
+
+type smartctlOutput struct {
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+	} `json:"smartctl"`
+	Device struct {
+		Name string `json:"name"`
+	} `json:"device"`
+	ModelName    string `json:"model_name"`
+	SerialNumber string `json:"serial_number"`
+	SmartStatus  *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature *struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime *struct {
+		Hours float64 `json:"hours"`
+	} `json:"power_on_time"`
+	ATASmartAttributes *struct {
+		Table []struct {
+			ID  int `json:"id"`
+			Raw struct {
+				Value float64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeHealth *struct {
+		MediaErrors    float64 `json:"media_errors"`
+		PercentageUsed float64 `json:"percentage_used"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+// Parse reads the output of `smartctl --json -a`.
+func Parse(data []byte) (models.DiskHealth, error) {
+	var health models.DiskHealth
+
+	var output smartctlOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return health, fmt.Errorf("invalid smartctl output: %w", err)
+	}
+	if output.Smartctl.ExitStatus&exitStatusFatal != 0 || output.SmartStatus == nil {
+		return health, fmt.Errorf("smartctl could not read SMART data, exit status %d", output.Smartctl.ExitStatus)
+	}
+
+	health.Device = output.Device.Name
+	health.Model = output.ModelName
+	health.Serial = output.SerialNumber
+	health.Passed = output.SmartStatus.Passed
+	if output.Temperature != nil {
+		health.TemperatureCelsius = &output.Temperature.Current
+	}
+	if output.PowerOnTime != nil {
+		health.PowerOnHours = &output.PowerOnTime.Hours
+	}
+	if output.ATASmartAttributes != nil {
+		for i := range output.ATASmartAttributes.Table {
+			attribute := &output.ATASmartAttributes.Table[i]
+			switch attribute.ID {
+			case attributeReallocatedSectors:
+				health.ReallocatedSectors = &attribute.Raw.Value
+			case attributePendingSectors:
+				health.PendingSectors = &attribute.Raw.Value
+			case attributeOfflineUncorrectable:
+				health.OfflineUncorrectable = &attribute.Raw.Value
+			}
+		}
+	}
+	if output.NVMeHealth != nil {
+		health.MediaErrors = &output.NVMeHealth.MediaErrors
+		health.PercentageUsed = &output.NVMeHealth.PercentageUsed
+	}
+	return health, nil
+}
+
+// ReadFile parses smartctl output saved to a file, e.g. by a cron job running `smartctl --json -a /dev/sda > sda.json`.
+// The modification time of the file is used as the time the disk was read.
+func ReadFile(path string) (models.DiskHealth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.DiskHealth{}, err
+	}
+	health, err := Parse(data)
+	if err != nil {
+		return health, fmt.Errorf("parsing %s failed: %w", path, err)
+	}
+	if info, err := os.Stat(path); err == nil {
+		health.ReadAt = info.ModTime()
+	}
+	return health, nil
+}
+
+// Run runs smartctl for a device. Disks in standby are not woken up, smartctl skips them with an error instead.
+func Run(smartctl string, device string) (models.DiskHealth, error) {
+	output, err := exec.Command(smartctl, "--json", "--all", "--nocheck=standby", device).Output()
+	// smartctl exits non-zero when the disk reports problems, the output is still valid then.
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return models.DiskHealth{}, err
+	}
+	health, err := Parse(output)
+	if err != nil {
+		return health, fmt.Errorf("smartctl for %s failed: %w", device, err)
+	}
+	health.Device = device
+	health.ReadAt = time.Now()
+	return health, nil
+}
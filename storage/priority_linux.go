@@ -0,0 +1,21 @@
+//go:build linux
+
+package storage
+
+import "syscall"
+
+const (
+	ioprioClassIdle  = 3
+	ioprioClassShift = 13
+	ioprioWhoProcess = 1
+)
+
+// lowerPriority moves the calling thread to the idle I/O scheduling class and the lowest CPU priority,
+// so scanning the storage directory does not compete with the storagenode for the disk.
+func lowerPriority() error {
+	tid := syscall.Gettid()
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), ioprioClassIdle<<ioprioClassShift); errno != 0 {
+		return errno
+	}
+	return syscall.Setpriority(syscall.PRIO_PROCESS, tid, 19)
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package storage
+
+// lowerPriority is only implemented on Linux, elsewhere the scan relies on its rate limit alone.
+func lowerPriority() error {
+	return nil
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"encoding/base32"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/akash329d/storj_exporter/api"
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Directories of the storagenode storage directory that are measured.
+var Directories = []string{"blobs", "trash", "temp"}
+
+// The storagenode names the per satellite directories after the lowercase, unpadded base32 encoding of the satellite ID.
+var satelliteDirEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// Scanner periodically measures the directories of a node's storage directory in the background.
+// Walking the blobs of a large node takes hours, so the scan runs with idle I/O priority, is limited
+// to a number of files per second and publishes the result of each satellite as soon as it is done.
+type Scanner struct {
+	Path     string
+	interval time.Duration
+	rate     int
+
+	mu    sync.Mutex
+	usage *models.DirectoryUsage
+}
+
+// NewScanner creates a scanner that waits interval between scans and visits at most rate files per second.
+// A rate of 0 disables the limit.
+func NewScanner(path string, interval time.Duration, rate int) *Scanner {
+	return &Scanner{
+		Path:     path,
+		interval: interval,
+		rate:     rate,
+	}
+}
+
+// Usage returns a copy of the scan results so far, or nil if no satellite has been scanned yet.
+func (s *Scanner) Usage() *models.DirectoryUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.usage == nil {
+		return nil
+	}
+
+	usage := *s.usage
+	usage.Directories = copyMap(s.usage.Directories)
+	usage.Satellites = copyMap(s.usage.Satellites)
+	usage.Trash = copyMap(s.usage.Trash)
+	return &usage
+}
+
+// Run scans the storage directory forever, it is meant to be run in its own goroutine.
+func (s *Scanner) Run() {
+	// The priority applies to the OS thread, so the scan has to stay on it.
+	runtime.LockOSThread()
+	if err := lowerPriority(); err != nil {
+		log.Printf("Error lowering priority of the storage scanner for %s: %v", s.Path, err)
+	}
+
+	for {
+		s.scan()
+		time.Sleep(s.interval)
+	}
+}
+
+func (s *Scanner) scan() {
+	start := time.Now()
+	limiter := newRateLimiter(s.rate)
+	result := &models.DirectoryUsage{
+		Directories: make(map[string]models.DirectorySize, len(Directories)),
+		Satellites:  make(map[models.SatelliteDirectory]models.DirectorySize),
+		Trash:       make(map[models.TrashDirectory]models.DirectorySize),
+	}
+
+	for _, directory := range []string{"blobs", "trash"} {
+		var total models.DirectorySize
+		for _, satelliteDir := range s.readDir(filepath.Join(s.Path, directory)) {
+			satelliteID := satelliteIDFromDir(satelliteDir.Name())
+			if !satelliteDir.IsDir() || satelliteID == "" {
+				continue
+			}
+
+			path := filepath.Join(s.Path, directory, satelliteDir.Name())
+			var size models.DirectorySize
+			if directory == "trash" {
+				size = s.measureTrash(path, satelliteID, result, limiter)
+			} else {
+				size = s.measure(path, limiter)
+			}
+			result.Satellites[models.SatelliteDirectory{SatelliteID: satelliteID, Directory: directory}] = size
+			total.Bytes += size.Bytes
+			total.Files += size.Files
+
+			s.publish(func(usage *models.DirectoryUsage) {
+				usage.Satellites[models.SatelliteDirectory{SatelliteID: satelliteID, Directory: directory}] = size
+				for trash, trashSize := range result.Trash {
+					if trash.SatelliteID == satelliteID {
+						usage.Trash[trash] = trashSize
+					}
+				}
+			})
+		}
+		result.Directories[directory] = total
+	}
+
+	result.Directories["temp"] = s.measure(filepath.Join(s.Path, "temp"), limiter)
+
+	// Replace the partial results, dropping satellites and trash dates that disappeared since the last scan.
+	result.ScannedAt = time.Now()
+	result.Duration = result.ScannedAt.Sub(start)
+	s.mu.Lock()
+	s.usage = result
+	s.mu.Unlock()
+}
+
+// measureTrash measures the trash of a satellite, broken down by the dated directories the node empties once they expire.
+// Nodes from before the dated trash layout keep all trash directly in the satellite directory, those are reported without a date.
+func (s *Scanner) measureTrash(path string, satelliteID string, result *models.DirectoryUsage, limiter *rateLimiter) models.DirectorySize {
+	var total models.DirectorySize
+	for _, entry := range s.readDir(path) {
+		date, err := time.Parse("2006-01-02", entry.Name())
+		if !entry.IsDir() || err != nil {
+			date = time.Time{}
+		}
+
+		size := s.measure(filepath.Join(path, entry.Name()), limiter)
+		key := models.TrashDirectory{SatelliteID: satelliteID, Date: date}
+		existing := result.Trash[key]
+		result.Trash[key] = models.DirectorySize{Bytes: existing.Bytes + size.Bytes, Files: existing.Files + size.Files}
+		total.Bytes += size.Bytes
+		total.Files += size.Files
+	}
+	return total
+}
+
+func (s *Scanner) measure(path string, limiter *rateLimiter) models.DirectorySize {
+	var size models.DirectorySize
+	_ = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Pieces are deleted and moved to the trash while we walk, skip whatever vanished. Other errors,
+			// such as a directory that cannot be read, skip that directory and the rest is still measured.
+			if !os.IsNotExist(err) {
+				log.Printf("Error scanning %s: %v", path, err)
+			}
+			if entry != nil && entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			limiter.wait()
+			if info, err := entry.Info(); err == nil {
+				size.Bytes += info.Size()
+				size.Files++
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+func (s *Scanner) readDir(path string) []os.DirEntry {
+	entries, err := os.ReadDir(path)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Error scanning %s: %v", path, err)
+	}
+	return entries
+}
+
+// publish applies a partial scan result to the usage reported to the collector.
+func (s *Scanner) publish(update func(usage *models.DirectoryUsage)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.usage == nil {
+		s.usage = &models.DirectoryUsage{
+			Directories: make(map[string]models.DirectorySize),
+			Satellites:  make(map[models.SatelliteDirectory]models.DirectorySize),
+			Trash:       make(map[models.TrashDirectory]models.DirectorySize),
+		}
+	}
+	update(s.usage)
+}
+
+func satelliteIDFromDir(name string) string {
+	id, err := satelliteDirEncoding.DecodeString(name)
+	if err != nil {
+		return ""
+	}
+	return api.EncodeNodeID(id)
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	copied := make(map[K]V, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+// rateLimiter limits the number of files visited per second.
+type rateLimiter struct {
+	rate        int
+	count       int
+	windowStart time.Time
+}
+
+func newRateLimiter(rate int) *rateLimiter {
+	return &rateLimiter{rate: rate, windowStart: time.Now()}
+}
+
+func (l *rateLimiter) wait() {
+	if l.rate <= 0 {
+		return
+	}
+	l.count++
+	if l.count < l.rate {
+		return
+	}
+	if elapsed := time.Since(l.windowStart); elapsed < time.Second {
+		time.Sleep(time.Second - elapsed)
+	}
+	l.count = 0
+	l.windowStart = time.Now()
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package storage
+
+import (
+	"syscall"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Statfs returns the size and free space of the filesystem containing path.
+func Statfs(path string) (models.FilesystemStats, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return models.FilesystemStats{}, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return models.FilesystemStats{
+		Size:       stat.Blocks * blockSize,
+		Free:       stat.Bfree * blockSize,
+		Available:  stat.Bavail * blockSize,
+		Inodes:     stat.Files,
+		InodesFree: stat.Ffree,
+	}, nil
+}
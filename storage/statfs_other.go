@@ -0,0 +1,14 @@
+//go:build !linux
+
+package storage
+
+import (
+	"errors"
+
+	"github.com/akash329d/storj_exporter/models"
+)
+
+// Statfs is only implemented on Linux.
+func Statfs(path string) (models.FilesystemStats, error) {
+	return models.FilesystemStats{}, errors.New("statfs is not supported on this platform")
+}